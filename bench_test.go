@@ -0,0 +1,180 @@
+package xerrors_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// The benchmarks in this file are the reference for performance changes, see testdata/bench_baseline.txt.
+// Run them with:
+//   go test -run '^$' -bench . -benchmem -count 10 > new.txt
+//   benchstat testdata/bench_baseline.txt new.txt
+
+// benchDepths are the chain lengths (number of non-StackError payloads) used across benchmarks.
+var benchDepths = []int{1, 4, 16, 64}
+
+// benchChain builds a chain with depth payloads on top of a single causal StackError.
+func benchChain(depth int) error {
+	err := xerrors.Wrap(nil, xerrors.New("cause"))
+	for i := 1; i < depth; i++ {
+		err = xerrors.Wrap(err, xerrors.New("wrapper-"+strconv.Itoa(i)))
+	}
+	return err
+}
+
+// benchChainWithTarget is as benchChain but holds a *ptrError as causal error.
+func benchChainWithTarget(depth int) error {
+	err := xerrors.Wrap(nil, &ptrError{"cause"})
+	for i := 1; i < depth; i++ {
+		err = xerrors.Wrap(err, xerrors.New("wrapper-"+strconv.Itoa(i)))
+	}
+	return err
+}
+
+func BenchmarkWrap(b *testing.B) {
+	for _, depth := range benchDepths {
+		err := benchChain(depth)
+		payload := xerrors.New("payload")
+
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = xerrors.Wrap(err, payload)
+			}
+		})
+	}
+
+	b.Run("unwrapped", func(b *testing.B) {
+		err, payload := xerrors.New("cause"), xerrors.New("payload")
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = xerrors.Wrap(err, payload)
+		}
+	})
+}
+
+func BenchmarkWrapWithOpts(b *testing.B) {
+	for _, opts := range []xerrors.StackOpts{{Depth: 0}, {Depth: 10}, {Depth: 32}} {
+		opts := opts
+
+		for _, depth := range benchDepths {
+			err := benchChain(depth)
+			payload := xerrors.New("payload")
+
+			b.Run("stack="+strconv.Itoa(int(opts.Depth))+"/depth="+strconv.Itoa(depth), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = xerrors.WrapWithOpts(err, payload, opts)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkPrinter(b *testing.B) {
+	printer := xerrors.NewPrinter(xerrors.NewColonFormatter)
+
+	for _, depth := range benchDepths {
+		err := benchChain(depth)
+
+		b.Run("Error/depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = err.Error()
+			}
+		})
+
+		b.Run("String/depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = printer.String(err)
+			}
+		})
+
+		b.Run("Write/depth="+strconv.Itoa(depth), func(b *testing.B) {
+			buf := &bytes.Buffer{}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				printer.Write(buf, err)
+				buf.Reset()
+			}
+		})
+	}
+}
+
+func BenchmarkFind(b *testing.B) {
+	isTarget := func(err error) bool {
+		_, ok := err.(*ptrError)
+		return ok
+	}
+
+	for _, depth := range benchDepths {
+		err := benchChainWithTarget(depth)
+
+		b.Run("Find/depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = xerrors.Find(err, isTarget)
+			}
+		})
+
+		b.Run("FindTyped/depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = xerrors.FindTyped(err, (*ptrError)(nil))
+			}
+		})
+	}
+}
+
+func BenchmarkSimilar(b *testing.B) {
+	for _, depth := range benchDepths {
+		err1, err2 := benchChain(depth), benchChain(depth)
+
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = xerrors.Similar(err1, err2)
+			}
+		})
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	for _, depth := range benchDepths {
+		err1, err2 := benchChain(depth), xerrors.Wrap(nil, xerrors.New("cause"))
+
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = xerrors.Contains(err1, err2)
+			}
+		})
+	}
+}
+
+func BenchmarkStackError(b *testing.B) {
+	stackErr := xerrors.Find(benchChain(1), isStackError).(*xerrors.StackError)
+
+	b.Run("Error", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = stackErr.Error()
+		}
+	})
+
+	b.Run("ErrorToBuffer", func(b *testing.B) {
+		buf := &bytes.Buffer{}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			stackErr.ErrorToBuffer(buf)
+			buf.Reset()
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package xerrors
+
+// Without returns err without the payloads passing pred, as a new chain.
+// WrappingErrors are immutable, so the nodes before the last removed payload are copied while the rest of the chain is
+// shared with err.
+// If err is not a WrappingError it returns nil if it passes pred, otherwise itself.
+// If every payload is removed the output is nil, and if pred is nil it is err.
+//
+// The expected use is removing internal details before returning an error to a caller:
+//
+//	return xerrors.Without(err, isInternalError)
+func Without(err error, pred func(error) bool) error {
+	return Replace(err, pred, func(error) error { return nil })
+}
+
+// StripStacks returns err without any StackError, as Without does.
+func StripStacks(err error) error {
+	return Without(err, isStackError)
+}
+
+// Replace returns err with the payloads passing pred replaced by the output of fn, as a new chain.
+// If fn returns nil the payload is removed, and if it returns a WrappingError its payloads take the place of the
+// replaced one.
+// As in Without, the chain after the last replaced payload is shared with err.
+// If err is not a WrappingError it returns fn(err) if it passes pred, otherwise itself.
+// If err, pred or fn are nil, err is returned.
+func Replace(err error, pred func(error) bool, fn func(error) error) error {
+	if err == nil || pred == nil || fn == nil {
+		return err
+	}
+
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		if !pred(err) {
+			return err
+		}
+		return fn(err)
+	}
+
+	var last *WrappingError
+	for current := find(wErr, pred); current != nil; current = find(current.next, pred) {
+		last = current
+	}
+
+	if last == nil {
+		return err
+	}
+
+	// a placeholder head, so the first node needs no special handling
+	head := &WrappingError{}
+	current := head
+
+	for ; wErr != last.next; wErr = wErr.next {
+		if !pred(wErr.payload) {
+			current.next = &WrappingError{payload: wErr.payload, internal: wErr.internal}
+			current = current.next
+			continue
+		}
+
+		switch rErr := fn(wErr.payload).(type) {
+		case nil:
+		case *WrappingError:
+			for ; rErr != nil; rErr = rErr.next {
+				current.next = &WrappingError{payload: rErr.payload, internal: rErr.internal}
+				current = current.next
+			}
+		default:
+			current.next = &WrappingError{payload: rErr}
+			current = current.next
+		}
+	}
+
+	current.next = last.next
+
+	if head.next == nil {
+		return nil
+	}
+
+	return head.next
+}
+
+// TruncateAfter returns err up to and including the first payload passing pred, dropping the rest of the chain.
+// The kept nodes are copied, as the last of them must point to nil.
+// If no payload passes pred, or err is not a WrappingError, or pred is nil, err is returned.
+func TruncateAfter(err error, pred func(error) bool) error {
+	wErr, ok := err.(*WrappingError)
+	if !ok || pred == nil {
+		return err
+	}
+
+	last := find(wErr, pred)
+	if last == nil || last.next == nil {
+		return err
+	}
+
+	out := &WrappingError{payload: wErr.payload, internal: wErr.internal}
+	current := out
+
+	for ; wErr != last; current = current.next {
+		wErr = wErr.next
+		current.next = &WrappingError{payload: wErr.payload, internal: wErr.internal}
+	}
+
+	return out
+}
@@ -0,0 +1,233 @@
+package xerrors
+
+import (
+	"testing"
+)
+
+// chainOf builds a chain out of the payloads, without any stacks unless given
+func chainOf(payloads ...error) *WrappingError {
+	var wErr *WrappingError
+	for i := len(payloads) - 1; i >= 0; i-- {
+		wErr = &WrappingError{payload: payloads[i], next: wErr}
+	}
+	return wErr
+}
+
+// payloadsOf describes the payloads of err, with "<stack>" for StackErrors
+func payloadsOf(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		return []string{"<unwrapped> " + err.Error()}
+	}
+
+	var out []string
+	for ; wErr != nil; wErr = wErr.next {
+		if isStackError(wErr.payload) {
+			out = append(out, "<stack>")
+			continue
+		}
+		out = append(out, wErr.payload.Error())
+	}
+
+	return out
+}
+
+func isFoo(err error) bool {
+	return err.Error() == "foo"
+}
+
+func TestChainFunctions(t *testing.T) {
+	stack := &StackError{}
+
+	scenarios := []struct {
+		name             string
+		err              error
+		f                func(error) error
+		expectedPayloads []string
+		// expectedShared is the number of trailing nodes expected to be shared with err
+		expectedShared int
+	}{
+		{
+			name:             "withoutNil",
+			err:              nil,
+			f:                func(err error) error { return Without(err, isFoo) },
+			expectedPayloads: nil,
+		},
+		{
+			name:             "withoutUnwrapped",
+			err:              New("foo"),
+			f:                func(err error) error { return Without(err, isFoo) },
+			expectedPayloads: nil,
+		},
+		{
+			name:             "withoutUnwrappedNoMatch",
+			err:              New("bar"),
+			f:                func(err error) error { return Without(err, isFoo) },
+			expectedPayloads: []string{"<unwrapped> bar"},
+		},
+		{
+			name:             "withoutNoMatch",
+			err:              chainOf(stack, New("bar"), New("baz")),
+			f:                func(err error) error { return Without(err, isFoo) },
+			expectedPayloads: []string{"<stack>", "bar", "baz"},
+			expectedShared:   3,
+		},
+		{
+			name:             "withoutMiddle",
+			err:              chainOf(stack, New("bar"), New("foo"), New("baz"), New("qux")),
+			f:                func(err error) error { return Without(err, isFoo) },
+			expectedPayloads: []string{"<stack>", "bar", "baz", "qux"},
+			expectedShared:   2,
+		},
+		{
+			name:             "withoutFirstAndLast",
+			err:              chainOf(New("foo"), stack, New("bar"), New("foo")),
+			f:                func(err error) error { return Without(err, isFoo) },
+			expectedPayloads: []string{"<stack>", "bar"},
+		},
+		{
+			name:             "withoutAll",
+			err:              chainOf(New("foo"), New("foo")),
+			f:                func(err error) error { return Without(err, isFoo) },
+			expectedPayloads: nil,
+		},
+		{
+			name:             "stripStacksTop",
+			err:              chainOf(stack, New("foo"), New("bar")),
+			f:                StripStacks,
+			expectedPayloads: []string{"foo", "bar"},
+			expectedShared:   2,
+		},
+		{
+			name:             "stripStacksMultiple",
+			err:              chainOf(New("foo"), stack, New("bar"), stack, New("baz")),
+			f:                StripStacks,
+			expectedPayloads: []string{"foo", "bar", "baz"},
+			expectedShared:   1,
+		},
+		{
+			name:             "stripStacksBottom",
+			err:              chainOf(New("foo"), New("bar"), stack),
+			f:                StripStacks,
+			expectedPayloads: []string{"foo", "bar"},
+		},
+		{
+			name: "replace",
+			err:  chainOf(stack, New("bar"), New("foo"), New("baz")),
+			f: func(err error) error {
+				return Replace(err, isFoo, func(error) error { return New("FOO") })
+			},
+			expectedPayloads: []string{"<stack>", "bar", "FOO", "baz"},
+			expectedShared:   1,
+		},
+		{
+			name: "replaceWithChain",
+			err:  chainOf(New("foo"), stack, New("bar")),
+			f: func(err error) error {
+				return Replace(err, isFoo, func(error) error { return chainOf(New("f1"), New("f2")) })
+			},
+			expectedPayloads: []string{"f1", "f2", "<stack>", "bar"},
+			expectedShared:   2,
+		},
+		{
+			name: "replaceWithNil",
+			err:  chainOf(New("bar"), New("foo"), stack),
+			f: func(err error) error {
+				return Replace(err, isFoo, func(error) error { return nil })
+			},
+			expectedPayloads: []string{"bar", "<stack>"},
+			expectedShared:   1,
+		},
+		{
+			name: "replaceUnwrapped",
+			err:  New("foo"),
+			f: func(err error) error {
+				return Replace(err, isFoo, func(error) error { return New("FOO") })
+			},
+			expectedPayloads: []string{"<unwrapped> FOO"},
+		},
+		{
+			name:             "truncateAfter",
+			err:              chainOf(stack, New("bar"), New("foo"), New("baz"), stack),
+			f:                func(err error) error { return TruncateAfter(err, isFoo) },
+			expectedPayloads: []string{"<stack>", "bar", "foo"},
+		},
+		{
+			name:             "truncateAfterStack",
+			err:              chainOf(New("foo"), stack, New("bar")),
+			f:                func(err error) error { return TruncateAfter(err, isStackError) },
+			expectedPayloads: []string{"foo", "<stack>"},
+		},
+		{
+			name:             "truncateAfterLast",
+			err:              chainOf(stack, New("bar"), New("foo")),
+			f:                func(err error) error { return TruncateAfter(err, isFoo) },
+			expectedPayloads: []string{"<stack>", "bar", "foo"},
+			expectedShared:   3,
+		},
+		{
+			name:             "truncateAfterNoMatch",
+			err:              chainOf(stack, New("bar")),
+			f:                func(err error) error { return TruncateAfter(err, isFoo) },
+			expectedPayloads: []string{"<stack>", "bar"},
+			expectedShared:   2,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var before []string
+			if s.err != nil {
+				before = payloadsOf(s.err)
+			}
+
+			out := s.f(s.err)
+
+			outPayloads := payloadsOf(out)
+			if len(outPayloads) != len(s.expectedPayloads) {
+				t.Fatalf("expected payloads %q, got %q", s.expectedPayloads, outPayloads)
+			}
+			for i := range outPayloads {
+				if outPayloads[i] != s.expectedPayloads[i] {
+					t.Fatalf("expected payloads %q, got %q", s.expectedPayloads, outPayloads)
+				}
+			}
+
+			// the input is never modified
+			if afterPayloads := payloadsOf(s.err); len(afterPayloads) != len(before) {
+				t.Errorf("input modified from %q to %q", before, afterPayloads)
+			}
+
+			wErr, ok := s.err.(*WrappingError)
+			if !ok {
+				return
+			}
+			outErr, _ := out.(*WrappingError)
+
+			if shared := sharedNodes(wErr, outErr); shared != s.expectedShared {
+				t.Errorf("expected %d shared nodes, got %d", s.expectedShared, shared)
+			}
+		})
+	}
+}
+
+// sharedNodes counts the nodes of out that are also nodes of in
+func sharedNodes(in, out *WrappingError) int {
+	nodes := map[*WrappingError]bool{}
+	for ; in != nil; in = in.next {
+		nodes[in] = true
+	}
+
+	var n int
+	for ; out != nil; out = out.next {
+		if nodes[out] {
+			n++
+		}
+	}
+
+	return n
+}
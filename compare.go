@@ -4,57 +4,146 @@ import (
 	"reflect"
 )
 
-// for two non-WrapperError errors, equal is true if they have matching types and Error() output
+// Equaler is an optional interface for errors defining their own equality, used by Similar, Contains and Diff.
+// Equal reports whether the argument, a non-WrappingError error, is logically identical to the receiver.
+// It is used in place of comparing types and Error() outputs, for example for errors holding timestamps or IDs in
+// their messages.
+//
+// [PROPOSAL NOTES]
+//
+// Equal must be symmetric: if only one of the errors compared implements Equaler, its Equal method is used.
+type Equaler interface {
+	Equal(error) bool
+}
+
+// for two non-WrapperError errors, equal is true if they have matching types and Error() output, or as defined by
+// Equaler
 func equal(err1, err2 error) bool {
 	if err1 == err2 {
 		// shortcut
 		return true
 	}
 
+	if eq, ok := err1.(Equaler); ok {
+		return eq.Equal(err2)
+	}
+
+	if eq, ok := err2.(Equaler); ok {
+		return eq.Equal(err1)
+	}
+
 	return reflect.TypeOf(err1) == reflect.TypeOf(err2) && err1.Error() == err2.Error()
 }
 
+// sameType is true if the two errors have matching types
+func sameType(err1, err2 error) bool {
+	return reflect.TypeOf(err1) == reflect.TypeOf(err2)
+}
+
 // for a non-WrapperError error, equalFunc returns a function that is true if its argument is of the same type and has
-// the same Error() output as err
+// the same Error() output as err, or as defined by Equaler
 func equalFunc(err error) func(error) bool {
+	if eq, ok := err.(Equaler); ok {
+		return eq.Equal
+	}
+
 	t := reflect.TypeOf(err)
 	msg := err.Error()
 
 	return func(err2 error) bool {
+		if eq, ok := err2.(Equaler); ok {
+			return eq.Equal(err)
+		}
+
 		return reflect.DeepEqual(reflect.TypeOf(err2), t) && err2.Error() == msg
 	}
 }
 
+// equalToFunc is as equalFunc for a custom comparison eq, nil for the default one
+func equalToFunc(err error, eq func(error, error) bool) func(error) bool {
+	if eq == nil {
+		return equalFunc(err)
+	}
+
+	return func(err1 error) bool {
+		return eq(err1, err)
+	}
+}
+
+// PayloadEqual is the comparison used by Similar, Contains and Diff for individual payloads.
+// Two payloads are equal if they have the same type and Error() output, unless either implements Equaler.
+// It is meant to be used along other comparisons in SimilarFunc, ContainsFunc and DiffFunc.
+func PayloadEqual(err1, err2 error) bool {
+	return equal(err1, err2)
+}
+
+// TypeOnly is a payload comparison for SimilarFunc, ContainsFunc and DiffFunc, true if the payloads have the same type.
+func TypeOnly(err1, err2 error) bool {
+	return sameType(err1, err2)
+}
+
+// MessageOnly is a payload comparison for SimilarFunc, ContainsFunc and DiffFunc, true if the payloads have the same
+// Error() output.
+// A nil error is only equal to another nil error.
+func MessageOnly(err1, err2 error) bool {
+	if err1 == nil || err2 == nil {
+		return err1 == err2
+	}
+
+	return err1.Error() == err2.Error()
+}
+
 // Similar compares to errors and validates if they are logically identical.
 // This involves checking all error types and Error() outputs are identical, but ignores wrapped StackErrors.
+// Payloads implementing Equaler are compared with it instead.
 // It is a replacement for reflect.DeepEqual(err1, err2) as the frame information will cause false negatives.
 //
 // [PROPOSAL NOTES]
 //
 // reflect.DeepEqual(err1, err2) to be migrated to use this
 func Similar(err1, err2 error) bool {
+	return similarFunc(err1, err2, equal)
+}
+
+// SimilarFunc is as Similar, but compares individual payloads with eq instead of PayloadEqual.
+// The first argument to eq is a payload of err1 and the second one of err2, neither is ever a StackError.
+// If eq is nil PayloadEqual is used.
+func SimilarFunc(err1, err2 error, eq func(error, error) bool) bool {
+	if eq == nil {
+		eq = equal
+	}
+
+	return similarFunc(err1, err2, eq)
+}
+
+func similarFunc(err1, err2 error, eq func(error, error) bool) bool {
+	if err1 == nil || err2 == nil {
+		// nil errors are only similar to nil errors, eq is never called with them
+		return err1 == err2
+	}
+
 	wErr1, ok1 := err1.(*WrappingError)
 	wErr2, ok2 := err2.(*WrappingError)
 
 	if !ok1 || !ok2 {
 		if ok2 {
-			return wErr2.next == nil && equal(err1, wErr2.payload)
+			return wErr2.next == nil && eq(err1, wErr2.payload)
 		}
 
 		if ok1 {
-			return wErr1.next == nil && equal(err2, wErr1.payload)
+			return wErr1.next == nil && eq(wErr1.payload, err2)
 		}
 
-		return equal(err1, err2)
+		return eq(err1, err2)
 	}
 
-	return similar(wErr1, wErr2)
+	return similar(wErr1, wErr2, eq)
 }
 
 // similar is the WrappingError-only form of Similar
-func similar(wErr1, wErr2 *WrappingError) bool {
+func similar(wErr1, wErr2 *WrappingError, eq func(error, error) bool) bool {
 	for wErr1, wErr2 = find(wErr1, isNotStackError), find(wErr2, isNotStackError); wErr1 != nil && wErr2 != nil; wErr1, wErr2 = find(wErr1.next, isNotStackError), find(wErr2.next, isNotStackError) {
-		if !equal(wErr1.payload, wErr2.payload) {
+		if !eq(wErr1.payload, wErr2.payload) {
 			return false
 		}
 	}
@@ -68,15 +157,33 @@ func similar(wErr1, wErr2 *WrappingError) bool {
 
 // Contains checks if err2 is logically contained within err1.
 // This involves checking all wrapped error types and Error() outputs in err2 appear in err1 in identical order.
+// Payloads implementing Equaler are compared with it instead.
 // It ignores wrapped FrameErrors altogether.
 //
 // [PROPOSAL NOTES]
 //
 // if err1 == err2 {...} comparisons to be migrated to use this
 func Contains(err1, err2 error) bool {
+	return containsFunc(err1, err2, nil)
+}
+
+// ContainsFunc is as Contains, but compares individual payloads with eq instead of PayloadEqual.
+// The first argument to eq is a payload of err1 and the second one of err2, neither is ever a StackError.
+// If eq is nil PayloadEqual is used.
+func ContainsFunc(err1, err2 error, eq func(error, error) bool) bool {
+	return containsFunc(err1, err2, eq)
+}
+
+// containsFunc is the form of Contains with a custom comparison eq, nil for the default one
+func containsFunc(err1, err2 error, eq func(error, error) bool) bool {
+	if err1 == nil || err2 == nil {
+		// nil errors only contain, and are only contained in, nil errors
+		return err1 == err2
+	}
+
 	wErr2, ok2 := err2.(*WrappingError)
 	if !ok2 {
-		return Find(err1, equalFunc(err2)) != nil
+		return Find(err1, equalToFunc(err2, eq)) != nil
 	}
 
 	wErr1, ok1 := err1.(*WrappingError)
@@ -84,16 +191,16 @@ func Contains(err1, err2 error) bool {
 		if wErr2.next != nil {
 			return false
 		}
-		return equal(err2, wErr2.payload)
+		return equalToFunc(wErr2.payload, eq)(err1)
 	}
 
-	return contains(wErr1, wErr2)
+	return contains(wErr1, wErr2, eq)
 }
 
 // contains is the WrappingError-only form of Contains
-func contains(wErr1, wErr2 *WrappingError) bool {
+func contains(wErr1, wErr2 *WrappingError, eq func(error, error) bool) bool {
 	for wErr2 = find(wErr2, isNotStackError); wErr2 != nil; wErr2 = find(wErr2.next, isNotStackError) {
-		wErr1 = find(wErr1, equalFunc(wErr2.payload))
+		wErr1 = find(wErr1, equalToFunc(wErr2.payload, eq))
 		if wErr1 == nil {
 			return false
 		}
@@ -2,6 +2,7 @@ package xerrors_test
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/JavierZunzunegui/xerrors"
@@ -188,3 +189,133 @@ func compareScenarios() []struct {
 		},
 	}
 }
+
+// idError is an error with an ID, which is not relevant for equality
+type idError struct {
+	id int
+}
+
+func (err idError) Error() string { return "id-" + strconv.Itoa(err.id) }
+
+func (err idError) Equal(other error) bool {
+	_, ok := other.(idError)
+	return ok
+}
+
+func TestSimilarFunc(t *testing.T) {
+	scenarios := []struct {
+		name            string
+		err1            error
+		err2            error
+		eq              func(error, error) bool
+		expectSimilar   bool
+		expectContained bool
+	}{
+		{
+			name:            "equalerDefault",
+			err1:            xerrors.Wrap(idError{1}, xerrors.New("foo")),
+			err2:            xerrors.Wrap(idError{2}, xerrors.New("foo")),
+			eq:              nil,
+			expectSimilar:   true,
+			expectContained: true,
+		},
+		{
+			name:            "equalerPayloadEqual",
+			err1:            xerrors.Wrap(idError{1}, xerrors.New("foo")),
+			err2:            idError{2},
+			eq:              xerrors.PayloadEqual,
+			expectSimilar:   false,
+			expectContained: true,
+		},
+		{
+			name:            "equalerMessageOnly",
+			err1:            xerrors.Wrap(idError{1}, xerrors.New("foo")),
+			err2:            xerrors.Wrap(idError{2}, xerrors.New("foo")),
+			eq:              xerrors.MessageOnly,
+			expectSimilar:   false,
+			expectContained: false,
+		},
+		{
+			name:            "typeOnly",
+			err1:            xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:            xerrors.Wrap(xerrors.New("baz"), xerrors.New("foobar")),
+			eq:              xerrors.TypeOnly,
+			expectSimilar:   true,
+			expectContained: true,
+		},
+		{
+			name:            "typeOnlyMismatch",
+			err1:            xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:            xerrors.Wrap(barError{}, xerrors.New("foo")),
+			eq:              xerrors.TypeOnly,
+			expectSimilar:   false,
+			expectContained: false,
+		},
+		{
+			name:            "messageOnly",
+			err1:            xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:            xerrors.Wrap(barError{}, xerrors.New("foo")),
+			eq:              xerrors.MessageOnly,
+			expectSimilar:   true,
+			expectContained: true,
+		},
+		{
+			name:            "nilMessageOnly",
+			err1:            nil,
+			err2:            xerrors.New("foo"),
+			eq:              xerrors.MessageOnly,
+			expectSimilar:   false,
+			expectContained: false,
+		},
+		{
+			name:            "nilContainedMessageOnly",
+			err1:            xerrors.New("foo"),
+			err2:            nil,
+			eq:              xerrors.MessageOnly,
+			expectSimilar:   false,
+			expectContained: false,
+		},
+		{
+			name:            "bothNilMessageOnly",
+			err1:            nil,
+			err2:            nil,
+			eq:              xerrors.MessageOnly,
+			expectSimilar:   true,
+			expectContained: true,
+		},
+		{
+			name:            "nilTypeOnly",
+			err1:            xerrors.Wrap(nil, xerrors.New("foo")),
+			err2:            nil,
+			eq:              xerrors.TypeOnly,
+			expectSimilar:   false,
+			expectContained: false,
+		},
+		{
+			name:            "messageOnlyContained",
+			err1:            xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:            barError{},
+			eq:              xerrors.MessageOnly,
+			expectSimilar:   false,
+			expectContained: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			if similar := xerrors.SimilarFunc(scenario.err1, scenario.err2, scenario.eq); similar != scenario.expectSimilar {
+				t.Fatalf("expected SimilarFunc to be %t", scenario.expectSimilar)
+			}
+
+			if contained := xerrors.ContainsFunc(scenario.err1, scenario.err2, scenario.eq); contained != scenario.expectContained {
+				t.Fatalf("expected ContainsFunc to be %t", scenario.expectContained)
+			}
+
+			if diff := xerrors.DiffFunc(scenario.err1, scenario.err2, scenario.eq); (diff == "") != scenario.expectSimilar {
+				t.Fatalf("expected DiffFunc to be empty only for similar errors, got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package xerrors
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+// ContextError is the payload recording the state of a context at the time its error was wrapped.
+// Do not initialise a ContextError directly, use ContextErr.
+type ContextError struct {
+	deadline    time.Time
+	hasDeadline bool
+	elapsed     time.Duration
+	cause       error
+}
+
+// Deadline is a getter for the context's deadline, as returned by context.Context.Deadline.
+func (err *ContextError) Deadline() (time.Time, bool) {
+	return err.deadline, err.hasDeadline
+}
+
+// Elapsed is a getter for the time waited on the context.
+// It is 0 if it was not recorded.
+func (err *ContextError) Elapsed() time.Duration {
+	return err.elapsed
+}
+
+// Cause is a getter for the context's cause, as returned by context.Cause.
+// It is nil if the cause is the context's error itself.
+func (err *ContextError) Cause() error {
+	return err.cause
+}
+
+// ErrorToBuffer makes ContextError implement BufferError.
+// The format is "context done[ after {elapsed}][ (cause: {cause})]".
+func (err *ContextError) ErrorToBuffer(buf *bytes.Buffer) {
+	buf.WriteString("context done")
+
+	if err.elapsed != 0 {
+		buf.WriteString(" after ")
+		buf.WriteString(err.elapsed.String())
+	}
+
+	if err.cause != nil {
+		buf.WriteString(" (cause: ")
+		buf.WriteString(err.cause.Error())
+		buf.WriteString(")")
+	}
+}
+
+// Error is the string format of ContextError.ErrorToBuffer
+func (err *ContextError) Error() string {
+	return BufferErrorToString(err)
+}
+
+// KeyValueErrorData makes ContextError implement KeyValueError.
+// The keys are "deadline" (RFC 3339), "elapsed" and "cause", each only present if recorded.
+func (err *ContextError) KeyValueErrorData() [][2]string {
+	var out [][2]string
+
+	if err.hasDeadline {
+		out = append(out, [2]string{"deadline", err.deadline.Format(time.RFC3339Nano)})
+	}
+
+	if err.elapsed != 0 {
+		out = append(out, [2]string{"elapsed", err.elapsed.String()})
+	}
+
+	if err.cause != nil {
+		out = append(out, [2]string{"cause", err.cause.Error()})
+	}
+
+	return out
+}
+
+// ContextErr wraps ctx.Err() with a ContextError and a stack, or returns nil if ctx is not done.
+// The time waited is measured from start, if start is the zero time it is not recorded.
+// The intended use is:
+//
+//	start := time.Now()
+//	select {
+//	case <-ctx.Done():
+//	  return xerrors.ContextErr(ctx, start)
+//	case ...
+//	}
+//
+// The stack starts from ContextErr, ContextErr not included.
+func ContextErr(ctx context.Context, start time.Time) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	cErr := &ContextError{}
+
+	cErr.deadline, cErr.hasDeadline = ctx.Deadline()
+
+	if !start.IsZero() {
+		cErr.elapsed = time.Since(start)
+	}
+
+	if cause := context.Cause(ctx); cause != err {
+		cErr.cause = cause
+	}
+
+	return frameWrap(
+		&WrappingError{
+			payload: cErr,
+			next:    &WrappingError{payload: err},
+		},
+		defaultStackOpts,
+	)
+}
+
+func isCanceled(err error) bool {
+	return err == context.Canceled
+}
+
+func isDeadlineExceeded(err error) bool {
+	return err == context.DeadlineExceeded
+}
+
+// IsCanceled reports whether context.Canceled is in the wrapping chain.
+func IsCanceled(err error) bool {
+	return Find(err, isCanceled) != nil
+}
+
+// IsDeadline reports whether context.DeadlineExceeded is in the wrapping chain.
+func IsDeadline(err error) bool {
+	return Find(err, isDeadlineExceeded) != nil
+}
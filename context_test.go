@@ -0,0 +1,106 @@
+package xerrors_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestContextErr(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	causeCtx, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(xerrors.New("shutting down"))
+
+	deadline := time.Now().Add(-time.Second)
+	deadlineCtx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	scenarios := []struct {
+		name             string
+		ctx              context.Context
+		start            time.Time
+		expectedOutput   string
+		expectedDeadline bool
+		expectCanceled   bool
+		expectDeadline   bool
+	}{
+		{
+			name: "notDone",
+			ctx:  context.Background(),
+		},
+		{
+			name:           "canceled",
+			ctx:            canceledCtx,
+			expectedOutput: "context done: context canceled",
+			expectCanceled: true,
+		},
+		{
+			name:           "canceledWithCause",
+			ctx:            causeCtx,
+			expectedOutput: "context done (cause: shutting down): context canceled",
+			expectCanceled: true,
+		},
+		{
+			name:             "deadline",
+			ctx:              deadlineCtx,
+			expectedOutput:   "context done: context deadline exceeded",
+			expectedDeadline: true,
+			expectDeadline:   true,
+		},
+		{
+			name:           "elapsed",
+			ctx:            canceledCtx,
+			start:          time.Now().Add(-time.Minute),
+			expectedOutput: "context done after 1m",
+			expectCanceled: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			err := xerrors.ContextErr(scenario.ctx, scenario.start)
+
+			if scenario.expectedOutput == "" {
+				if err != nil {
+					t.Fatalf("expected nil error, got %q", err)
+				}
+				return
+			}
+
+			if out := err.Error(); !strings.HasPrefix(out, scenario.expectedOutput) {
+				t.Fatalf("expected prefix %q got %q", scenario.expectedOutput, out)
+			}
+
+			cErr, ok := xerrors.FindTyped(err, (*xerrors.ContextError)(nil)).(*xerrors.ContextError)
+			if !ok {
+				t.Fatal("expected to find a ContextError")
+			}
+
+			if d, ok := cErr.Deadline(); ok != scenario.expectedDeadline || (ok && !d.Equal(deadline)) {
+				t.Fatalf("unexpected deadline %v (%t)", d, ok)
+			}
+
+			if xerrors.Find(err, isStackError) == nil {
+				t.Fatal("expected to find a StackError")
+			}
+
+			// checking through an additional wrapper
+			err = xerrors.Wrap(err, xerrors.New("wrapper"))
+
+			if xerrors.IsCanceled(err) != scenario.expectCanceled {
+				t.Fatalf("expected IsCanceled to be %t", scenario.expectCanceled)
+			}
+
+			if xerrors.IsDeadline(err) != scenario.expectDeadline {
+				t.Fatalf("expected IsDeadline to be %t", scenario.expectDeadline)
+			}
+		})
+	}
+}
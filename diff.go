@@ -0,0 +1,322 @@
+package xerrors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DiffKind classifies a payload in the comparison of two errors, see DiffPayloads.
+type DiffKind uint8
+
+const (
+	// DiffEqual is for payloads present in both errors at matching positions.
+	DiffEqual DiffKind = iota
+	// DiffMoved is for payloads present in both errors but out of order.
+	DiffMoved
+	// DiffType is for payloads at matching positions that have different types.
+	DiffType
+	// DiffMessage is for payloads at matching positions that have the same type but different Error() output.
+	DiffMessage
+	// DiffMissing is for payloads present only in the first error.
+	DiffMissing
+	// DiffExtra is for payloads present only in the second error.
+	DiffExtra
+)
+
+var diffKindNames = [...]string{
+	DiffEqual:   "equal",
+	DiffMoved:   "moved",
+	DiffType:    "type",
+	DiffMessage: "message",
+	DiffMissing: "missing",
+	DiffExtra:   "extra",
+}
+
+func (k DiffKind) String() string {
+	if int(k) < len(diffKindNames) {
+		return diffKindNames[k]
+	}
+	return "DiffKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// PayloadDiff is the comparison of a payload in two errors, see DiffPayloads.
+// Indexes are positions amongst the non-StackError payloads of each error, starting at 0, and are -1 if absent.
+type PayloadDiff struct {
+	Kind               DiffKind
+	Index1, Index2     int
+	Payload1, Payload2 error
+}
+
+// String is the compact form of the PayloadDiff, "{kind} {index1} {index2}" with absent indexes as "-".
+// For example "message 1 1", "moved 0 2" or "missing 3 -".
+func (d PayloadDiff) String() string {
+	return d.Kind.String() + " " + diffIndex(d.Index1) + " " + diffIndex(d.Index2)
+}
+
+func diffIndex(i int) string {
+	if i == -1 {
+		return "-"
+	}
+	return strconv.Itoa(i)
+}
+
+// DiffPayloads compares two errors payload by payload, ignoring StackErrors as Similar does.
+// Payloads are aligned by their longest common (ordered) subsequence, and the remaining ones paired by position.
+// The output has an entry for every payload of both errors, in order of appearance.
+// All entries are DiffEqual if the errors are Similar.
+func DiffPayloads(err1, err2 error) []PayloadDiff {
+	return diffPayloads(diffList(err1), diffList(err2), equal)
+}
+
+// DiffPayloadsFunc is as DiffPayloads, but compares individual payloads with eq instead of PayloadEqual.
+// Payloads that are not equal are still reported as DiffType or DiffMessage based on their types.
+// If eq is nil PayloadEqual is used.
+func DiffPayloadsFunc(err1, err2 error, eq func(error, error) bool) []PayloadDiff {
+	if eq == nil {
+		eq = equal
+	}
+
+	return diffPayloads(diffList(err1), diffList(err2), eq)
+}
+
+// diffList lists the non-StackError payloads in err
+func diffList(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		wErr = &WrappingError{payload: err}
+	}
+
+	var out []error
+	for wErr = find(wErr, isNotStackError); wErr != nil; wErr = find(wErr.next, isNotStackError) {
+		out = append(out, wErr.payload)
+	}
+
+	return out
+}
+
+func diffPayloads(l1, l2 []error, eq func(error, error) bool) []PayloadDiff {
+	// lcs[i][j] is the length of the longest common subsequence of l1[i:] and l2[j:]
+	lcs := make([][]int, len(l1)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(l2)+1)
+	}
+	for i := len(l1) - 1; i >= 0; i-- {
+		for j := len(l2) - 1; j >= 0; j-- {
+			if eq(l1[i], l2[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// match1[i] is the index in l2 matched to l1[i], or -1 if unmatched, and conversely for match2
+	match1, match2 := make([]int, len(l1)), make([]int, len(l2))
+	for i := range match1 {
+		match1[i] = -1
+	}
+	for j := range match2 {
+		match2[j] = -1
+	}
+
+	for i, j := 0, 0; i < len(l1) && j < len(l2); {
+		switch {
+		case eq(l1[i], l2[j]) && lcs[i][j] == lcs[i+1][j+1]+1:
+			match1[i], match2[j] = j, i
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	// out of order payloads, matched outside the common subsequence
+	moved1, moved2 := make([]int, len(l1)), make([]int, len(l2))
+	for i := range moved1 {
+		moved1[i] = -1
+		if match1[i] != -1 {
+			continue
+		}
+		for j := range l2 {
+			if match2[j] == -1 && moved2[j] == 0 && eq(l1[i], l2[j]) {
+				moved1[i], moved2[j] = j, 1
+				break
+			}
+		}
+	}
+
+	out := make([]PayloadDiff, 0, len(l1)+len(l2))
+
+	// walking the gaps between matches, and the match closing each gap
+	for i, j := 0, 0; i <= len(l1) && j <= len(l2); i, j = i+1, j+1 {
+		gapEnd1, gapEnd2 := i, j
+		for ; gapEnd1 < len(l1) && match1[gapEnd1] == -1; gapEnd1++ {
+		}
+		for ; gapEnd2 < len(l2) && match2[gapEnd2] == -1; gapEnd2++ {
+		}
+
+		for ; i < gapEnd1; i++ {
+			if moved1[i] != -1 {
+				out = append(out, PayloadDiff{DiffMoved, i, moved1[i], l1[i], l2[moved1[i]]})
+				continue
+			}
+
+			for ; j < gapEnd2 && moved2[j] != 0; j++ {
+			}
+
+			if j == gapEnd2 {
+				out = append(out, PayloadDiff{DiffMissing, i, -1, l1[i], nil})
+				continue
+			}
+
+			kind := DiffType
+			if sameType(l1[i], l2[j]) {
+				kind = DiffMessage
+			}
+			out = append(out, PayloadDiff{kind, i, j, l1[i], l2[j]})
+			j++
+		}
+
+		for ; j < gapEnd2; j++ {
+			if moved2[j] == 0 {
+				out = append(out, PayloadDiff{DiffExtra, -1, j, nil, l2[j]})
+			}
+		}
+
+		if i == len(l1) || j == len(l2) {
+			break
+		}
+
+		out = append(out, PayloadDiff{DiffEqual, i, j, l1[i], l2[j]})
+	}
+
+	return out
+}
+
+// Diff produces a human-readable comparison of two errors, with a line for every entry in DiffPayloads.
+// It returns the empty string if the errors are Similar.
+// It is intended to explain failed comparisons, for example in tests.
+//
+// The format of the lines is:
+//
+//	= [{index1}/{index2}] {type} {message}
+//	~ [{index1}/{index2}] moved: {type} {message}
+//	! [{index1}/{index2}] type: {type1} {message1} != {type2} {message2}
+//	! [{index1}/{index2}] message: {type} {message1} != {message2}
+//	- [{index1}/-] {type} {message}
+//	+ [-/{index2}] {type} {message}
+//
+// Types are printed as by TypeName and messages are quoted.
+// If all payloads are equal but the errors are still not Similar, as for an unwrapped error and the same error wrapped
+// along a StackError, the first line is instead:
+//
+//	! wrapping: {nil|unwrapped|wrapped} != {nil|unwrapped|wrapped}
+func Diff(err1, err2 error) string {
+	return diff(err1, err2, equal)
+}
+
+// DiffFunc is as Diff, but compares individual payloads with eq instead of PayloadEqual, see DiffPayloadsFunc.
+// It returns the empty string if the errors are similar according to SimilarFunc.
+func DiffFunc(err1, err2 error, eq func(error, error) bool) string {
+	if eq == nil {
+		eq = equal
+	}
+
+	return diff(err1, err2, eq)
+}
+
+func diff(err1, err2 error, eq func(error, error) bool) string {
+	diffs := diffPayloads(diffList(err1), diffList(err2), eq)
+
+	different := false
+	for _, d := range diffs {
+		if d.Kind != DiffEqual {
+			different = true
+			break
+		}
+	}
+
+	if different {
+		return formatDiff(diffs, "")
+	}
+
+	if similarFunc(err1, err2, eq) {
+		return ""
+	}
+
+	return formatDiff(diffs, "! wrapping: "+wrapping(err1)+" != "+wrapping(err2))
+}
+
+// wrapping describes whether err is nil, a WrappingError or neither, for Diff
+func wrapping(err error) string {
+	switch err.(type) {
+	case nil:
+		return "nil"
+	case *WrappingError:
+		return "wrapped"
+	}
+	return "unwrapped"
+}
+
+// formatDiff formats the diffs as described in Diff, after header if it is not empty
+func formatDiff(diffs []PayloadDiff, header string) string {
+	var b strings.Builder
+
+	b.WriteString(header)
+
+	for _, d := range diffs {
+		if b.Len() != 0 {
+			b.WriteString("\n")
+		}
+
+		switch d.Kind {
+		case DiffEqual:
+			b.WriteString("= ")
+		case DiffMoved:
+			b.WriteString("~ ")
+		case DiffType, DiffMessage:
+			b.WriteString("! ")
+		case DiffMissing:
+			b.WriteString("- ")
+		case DiffExtra:
+			b.WriteString("+ ")
+		}
+
+		b.WriteString("[" + diffIndex(d.Index1) + "/" + diffIndex(d.Index2) + "] ")
+
+		switch d.Kind {
+		case DiffMoved:
+			b.WriteString("moved: ")
+		case DiffType:
+			b.WriteString("type: ")
+		case DiffMessage:
+			b.WriteString("message: ")
+		}
+
+		p := d.Payload1
+		if p == nil {
+			p = d.Payload2
+		}
+
+		b.WriteString(TypeName(p))
+		b.WriteString(" ")
+		b.WriteString(strconv.Quote(p.Error()))
+
+		switch d.Kind {
+		case DiffType:
+			b.WriteString(" != " + TypeName(d.Payload2) + " " + strconv.Quote(d.Payload2.Error()))
+		case DiffMessage:
+			b.WriteString(" != " + strconv.Quote(d.Payload2.Error()))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package xerrors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestDiffPayloads(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		err1        error
+		err2        error
+		expectedOut []string
+	}{
+		{
+			name:        "nil",
+			err1:        nil,
+			err2:        nil,
+			expectedOut: []string{},
+		},
+		{
+			name:        "similar",
+			err1:        xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:        xerrors.WrapWithOpts(xerrors.New("bar"), xerrors.New("foo"), xerrors.StackOpts{}),
+			expectedOut: []string{"equal 0 0", "equal 1 1"},
+		},
+		{
+			name:        "unwrappedMessage",
+			err1:        xerrors.New("foo"),
+			err2:        xerrors.New("bar"),
+			expectedOut: []string{"message 0 0"},
+		},
+		{
+			name:        "unwrappedType",
+			err1:        barError{},
+			err2:        xerrors.New("bar"),
+			expectedOut: []string{"type 0 0"},
+		},
+		{
+			name:        "message",
+			err1:        xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:        xerrors.Wrap(xerrors.New("baz"), xerrors.New("foo")),
+			expectedOut: []string{"equal 0 0", "message 1 1"},
+		},
+		{
+			name:        "missing",
+			err1:        xerrors.Wrap(xerrors.Wrap(xerrors.New("bar"), xerrors.New("foobar")), xerrors.New("foo")),
+			err2:        xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			expectedOut: []string{"equal 0 0", "missing 1 -", "equal 2 1"},
+		},
+		{
+			name:        "extra",
+			err1:        xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:        xerrors.Wrap(xerrors.Wrap(xerrors.New("bar"), xerrors.New("foobar")), xerrors.New("foo")),
+			expectedOut: []string{"equal 0 0", "extra - 1", "equal 1 2"},
+		},
+		{
+			name:        "moved",
+			err1:        xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			err2:        xerrors.Wrap(xerrors.New("foo"), xerrors.New("bar")),
+			expectedOut: []string{"moved 0 1", "equal 1 0"},
+		},
+		{
+			name:        "mixed",
+			err1:        xerrors.Wrap(xerrors.Wrap(xerrors.New("cause"), barError{}), xerrors.New("foo")),
+			err2:        xerrors.Wrap(xerrors.Wrap(xerrors.Wrap(xerrors.New("cause"), xerrors.New("new")), xerrors.New("bar")), xerrors.New("foo2")),
+			expectedOut: []string{"message 0 0", "type 1 1", "extra - 2", "equal 2 3"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			diffs := xerrors.DiffPayloads(scenario.err1, scenario.err2)
+
+			out := make([]string, len(diffs))
+			for i, d := range diffs {
+				out[i] = d.String()
+			}
+
+			if !reflect.DeepEqual(out, scenario.expectedOut) {
+				t.Fatalf("expected %q got %q", scenario.expectedOut, out)
+			}
+
+			if similar := xerrors.Similar(scenario.err1, scenario.err2); similar != (xerrors.Diff(scenario.err1, scenario.err2) == "") {
+				t.Fatalf("expected Diff to be empty if and only if the errors are similar (%t)", similar)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	err1 := xerrors.Wrap(xerrors.Wrap(xerrors.New("cause"), barError{}), xerrors.New("foo"))
+	err2 := xerrors.Wrap(xerrors.Wrap(xerrors.Wrap(xerrors.New("cause"), xerrors.New("new")), xerrors.New("bar")), xerrors.New("foo2"))
+
+	const expectedOut = "" +
+		`! [0/0] message: xerrors.string "foo" != "foo2"` + "\n" +
+		`! [1/1] type: xerrors_test.barError "bar" != xerrors.string "bar"` + "\n" +
+		`+ [-/2] xerrors.string "new"` + "\n" +
+		`= [2/3] xerrors.string "cause"`
+
+	if out := xerrors.Diff(err1, err2); out != expectedOut {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expectedOut, out)
+	}
+}
+
+func TestDiff_wrapping(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		err1        error
+		err2        error
+		expectedOut string
+	}{
+		{
+			name:        "similar",
+			err1:        xerrors.Wrap(nil, xerrors.New("foo")),
+			err2:        xerrors.Wrap(nil, xerrors.New("foo")),
+			expectedOut: "",
+		},
+		{
+			name: "unwrappedAndWrapped",
+			err1: xerrors.New("foo"),
+			err2: xerrors.Wrap(nil, xerrors.New("foo")),
+			expectedOut: "! wrapping: unwrapped != wrapped\n" +
+				`= [0/0] xerrors.string "foo"`,
+		},
+		{
+			name:        "nilAndWrapped",
+			err1:        nil,
+			err2:        xerrors.Wrap(nil, xerrors.New("foo")),
+			expectedOut: `+ [-/0] xerrors.string "foo"`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := xerrors.Diff(s.err1, s.err2); out != s.expectedOut {
+				t.Fatalf("expected:\n%s\ngot:\n%s", s.expectedOut, out)
+			}
+
+			if (s.expectedOut == "") != xerrors.Similar(s.err1, s.err2) {
+				t.Fatal("expected Diff to be empty only for Similar errors")
+			}
+		})
+	}
+}
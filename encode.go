@@ -0,0 +1,304 @@
+package xerrors
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"runtime"
+)
+
+// RemoteError is the payload of decoded errors whose type is not registered in this process, see Register.
+// Do not initialise a RemoteError directly, use DecodeJSON or DecodeBinary.
+type RemoteError struct {
+	typeName string
+	msg      string
+	fields   [][2]string
+}
+
+// TypeName is a getter for the name of the type of the original error, as it was encoded.
+func (err *RemoteError) TypeName() string {
+	return err.typeName
+}
+
+// Error returns the message of the original error.
+func (err *RemoteError) Error() string {
+	return err.msg
+}
+
+// KeyValueErrorData makes RemoteError implement KeyValueError, returning the structured data of the original error.
+func (err *RemoteError) KeyValueErrorData() [][2]string {
+	return err.fields
+}
+
+// Equal makes RemoteError implement Equaler, so decoded errors remain comparable via Similar and Contains.
+// It is true for errors with the same TypeName and Error() output, be they RemoteErrors or errors of this process such
+// as the original one.
+func (err *RemoteError) Equal(err2 error) bool {
+	return err2 != nil && TypeName(err2) == err.typeName && err2.Error() == err.msg
+}
+
+// wirePayload is the encoded form of a single payload
+type wirePayload struct {
+	Type    string      `json:"type"`
+	Message string      `json:"message,omitempty"`
+	Fields  [][2]string `json:"fields,omitempty"`
+	Frames  []wireFrame `json:"frames,omitempty"`
+}
+
+type wireFrame struct {
+	Function string `json:"function,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+func toWire(err error) []wirePayload {
+	if err == nil {
+		return nil
+	}
+
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		wErr = &WrappingError{payload: err}
+	}
+
+	var out []wirePayload
+
+	for ; wErr != nil; wErr = wErr.next {
+		out = append(out, payloadToWire(wErr.payload))
+	}
+
+	return out
+}
+
+func payloadToWire(err error) wirePayload {
+	if sErr, ok := err.(*StackError); ok {
+		frames := sErr.SymbolizedFrames()
+
+		p := wirePayload{
+			Type:   typeName(err),
+			Frames: make([]wireFrame, len(frames)),
+		}
+
+		for i, frame := range frames {
+			// paths are trimmed as when formatting, to not leak details of the machine the error originated in
+			p.Frames[i] = wireFrame{
+				Function: frame.Function,
+				File:     trimPath(frame.Function, frame.File, sErr.trim, mainModule, mainPackage),
+				Line:     frame.Line,
+			}
+		}
+
+		return p
+	}
+
+	p := wirePayload{
+		Type:    typeName(err),
+		Message: err.Error(),
+	}
+
+	if c := lookupError(err); c != nil && c.encode != nil {
+		p.Fields = c.encode(err)
+	} else if kvErr, ok := err.(KeyValueError); ok {
+		p.Fields = kvErr.KeyValueErrorData()
+	}
+
+	return p
+}
+
+// fromWire reconstructs the encoded error, returning it along a nil error to match the decoding functions
+func fromWire(payloads []wirePayload) (error, error) {
+	var out, current *WrappingError
+
+	for _, p := range payloads {
+		wErr := &WrappingError{payload: payloadFromWire(p)}
+
+		if out == nil {
+			out = wErr
+		} else {
+			current.next = wErr
+		}
+		current = wErr
+	}
+
+	if out == nil {
+		// as a nil error, not a nil *WrappingError
+		return nil, nil
+	}
+
+	return out, nil
+}
+
+func payloadFromWire(p wirePayload) error {
+	if p.Type == stackTypeName {
+		sErr := &StackError{symbolized: make([]runtime.Frame, len(p.Frames))}
+
+		for i, frame := range p.Frames {
+			sErr.symbolized[i] = runtime.Frame{Function: frame.Function, File: frame.File, Line: frame.Line}
+		}
+
+		return sErr
+	}
+
+	if c := lookupName(p.Type); c != nil {
+		if err := c.decode(p.Message, p.Fields); err != nil {
+			return err
+		}
+	}
+
+	return &RemoteError{typeName: p.Type, msg: p.Message, fields: p.Fields}
+}
+
+// EncodeJSON encodes err in a JSON form, from which it can be reconstructed via DecodeJSON.
+// Every payload is encoded with its type name (see TypeName), message and structured data (see KeyValueError),
+// StackErrors with their symbolized frames, file paths trimmed as by StackOpts.Trim.
+// A nil err is encoded as an empty list.
+//
+// [PROPOSAL NOTES]
+//
+// The JSON and binary forms are equivalent, JSON is meant for human-readable transports and binary for efficiency.
+func EncodeJSON(err error) []byte {
+	payloads := toWire(err)
+	if payloads == nil {
+		payloads = []wirePayload{}
+	}
+
+	// marshalling strings and ints never fails
+	b, _ := json.Marshal(payloads)
+
+	return b
+}
+
+// DecodeJSON reconstructs an error encoded via EncodeJSON.
+// Payloads whose type is registered in this process (see Register) are decoded to their original type, other payloads
+// are decoded as RemoteErrors and StackErrors hold only symbolized frames (see StackError.SymbolizedFrames).
+// The first returned error is the decoded one, a WrappingError or nil for an encoded nil error.
+// The second returned error is non-nil if data is not a valid encoding.
+func DecodeJSON(data []byte) (error, error) {
+	var payloads []wirePayload
+	if err := json.Unmarshal(data, &payloads); err != nil {
+		return nil, Wrap(err, errMalformedEncoding)
+	}
+
+	return fromWire(payloads)
+}
+
+// binaryVersion is the first byte of all binary encodings, allowing for changes of format
+const binaryVersion = 1
+
+var errMalformedEncoding = New("xerrors: malformed encoding")
+
+// EncodeBinary encodes err in a compact binary form, from which it can be reconstructed via DecodeBinary.
+// It is the binary equivalent of EncodeJSON.
+func EncodeBinary(err error) []byte {
+	payloads := toWire(err)
+
+	b := []byte{binaryVersion}
+	b = binary.AppendUvarint(b, uint64(len(payloads)))
+
+	for _, p := range payloads {
+		b = appendString(b, p.Type)
+		b = appendString(b, p.Message)
+
+		b = binary.AppendUvarint(b, uint64(len(p.Fields)))
+		for _, kv := range p.Fields {
+			b = appendString(b, kv[0])
+			b = appendString(b, kv[1])
+		}
+
+		b = binary.AppendUvarint(b, uint64(len(p.Frames)))
+		for _, frame := range p.Frames {
+			b = appendString(b, frame.Function)
+			b = appendString(b, frame.File)
+			b = binary.AppendUvarint(b, uint64(frame.Line))
+		}
+	}
+
+	return b
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// DecodeBinary reconstructs an error encoded via EncodeBinary.
+// It is the binary equivalent of DecodeJSON.
+func DecodeBinary(data []byte) (error, error) {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return nil, Wrap(nil, errMalformedEncoding)
+	}
+
+	r := binaryReader{b: data[1:], ok: true}
+
+	payloads := make([]wirePayload, r.length())
+	for i := 0; i < len(payloads) && r.ok; i++ {
+		p := &payloads[i]
+
+		p.Type = r.string()
+		p.Message = r.string()
+
+		if n := r.length(); n != 0 {
+			p.Fields = make([][2]string, n)
+			for j := range p.Fields {
+				p.Fields[j] = [2]string{r.string(), r.string()}
+			}
+		}
+
+		if n := r.length(); n != 0 {
+			p.Frames = make([]wireFrame, n)
+			for j := range p.Frames {
+				p.Frames[j] = wireFrame{Function: r.string(), File: r.string(), Line: int(r.uvarint())}
+			}
+		}
+	}
+
+	if !r.ok || len(r.b) != 0 {
+		return nil, Wrap(nil, errMalformedEncoding)
+	}
+
+	return fromWire(payloads)
+}
+
+// binaryReader reads the binary encoding, ok becomes false and all reads return zero values once malformed
+type binaryReader struct {
+	b  []byte
+	ok bool
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if !r.ok {
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.ok = false
+		return 0
+	}
+
+	r.b = r.b[n:]
+
+	return v
+}
+
+// length reads a count, which may be no bigger than the remaining bytes (every element takes at least one byte)
+func (r *binaryReader) length() int {
+	v := r.uvarint()
+	if v > uint64(len(r.b)) {
+		r.ok = false
+		return 0
+	}
+
+	return int(v)
+}
+
+func (r *binaryReader) string() string {
+	n := r.length()
+	if !r.ok {
+		return ""
+	}
+
+	s := string(r.b[:n])
+	r.b = r.b[n:]
+
+	return s
+}
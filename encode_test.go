@@ -0,0 +1,238 @@
+package xerrors_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func encodingScenarios() []struct {
+	name      string
+	encode    func(error) []byte
+	decode    func([]byte) (error, error)
+	malformed []byte
+} {
+	return []struct {
+		name      string
+		encode    func(error) []byte
+		decode    func([]byte) (error, error)
+		malformed []byte
+	}{
+		{
+			name:      "JSON",
+			encode:    xerrors.EncodeJSON,
+			decode:    xerrors.DecodeJSON,
+			malformed: []byte(`[{"type":`),
+		},
+		{
+			name:      "binary",
+			encode:    xerrors.EncodeBinary,
+			decode:    xerrors.DecodeBinary,
+			malformed: xerrors.EncodeBinary(xerrors.New("foo"))[:5],
+		},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	canceledCtx, cancel := context.WithCancelCause(context.Background())
+	cancel(xerrors.New("shutting down"))
+
+	errs := []struct {
+		name          string
+		err           error
+		expectSimilar bool
+	}{
+		{
+			name:          "nil",
+			err:           nil,
+			expectSimilar: true,
+		},
+		{
+			name:          "unwrapped",
+			err:           xerrors.New("foo"),
+			expectSimilar: true,
+		},
+		{
+			name:          "wrapped",
+			err:           xerrors.Wrap(xerrors.New("bar"), xerrors.New("foo")),
+			expectSimilar: true,
+		},
+		{
+			name:          "context",
+			err:           xerrors.Wrap(xerrors.ContextErr(canceledCtx, time.Now().Add(-time.Second)), xerrors.New("foo")),
+			expectSimilar: true,
+		},
+		{
+			name:          "panic",
+			err:           <-xerrors.Go(func() error { panic("boom") }),
+			expectSimilar: true,
+		},
+		{
+			name: "retry",
+			err: xerrors.Retry(context.Background(), xerrors.RetryPolicy{MaxAttempts: 2, Sleep: noSleep}, func() error {
+				return xerrors.Wrap(xerrors.New("foo"), xerrors.RetryAfter(time.Second))
+			}),
+			expectSimilar: true,
+		},
+		{
+			name:          "severity",
+			err:           xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityWarn),
+			expectSimilar: true,
+		},
+		{
+			name:          "unknownType",
+			err:           foo(),
+			expectSimilar: true,
+		},
+		{
+			name:          "stdlib",
+			err:           xerrors.Wrap(io.EOF, fmt.Errorf("reading %d", 3)),
+			expectSimilar: true,
+		},
+	}
+
+	for _, scenario := range encodingScenarios() {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			for _, e := range errs {
+				e := e
+
+				t.Run(e.name, func(t *testing.T) {
+					wErr, err := scenario.decode(scenario.encode(e.err))
+					if err != nil {
+						t.Fatalf("unexpected decoding error: %s", err)
+					}
+
+					if e.err == nil {
+						if wErr != nil {
+							t.Fatalf("expected nil error, got %q", wErr)
+						}
+						return
+					}
+
+					if out, expectedOut := wErr.Error(), e.err.Error(); out != expectedOut {
+						t.Fatalf("expected %q got %q", expectedOut, out)
+					}
+
+					if similar := xerrors.Similar(e.err, wErr); similar != e.expectSimilar {
+						t.Fatalf("expected Similar to be %t", e.expectSimilar)
+					}
+
+					if stackErr, ok := xerrors.Find(e.err, isStackError).(*xerrors.StackError); ok {
+						decodedStackErr, ok := xerrors.Find(wErr, isStackError).(*xerrors.StackError)
+						if !ok {
+							t.Fatal("expected to find a decoded StackError")
+						}
+
+						if out, expectedOut := decodedStackErr.Error(), stackErr.Error(); out != expectedOut {
+							t.Fatalf("expected stack %q got %q", expectedOut, out)
+						}
+					}
+
+					// encoding is stable through decoding
+					if !reflect.DeepEqual(scenario.encode(e.err), scenario.encode(wErr)) {
+						t.Fatal("expected decoded error to encode as the original")
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestDecode_remoteError(t *testing.T) {
+	for _, scenario := range encodingScenarios() {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			wErr, err := scenario.decode(scenario.encode(foo()))
+			if err != nil {
+				t.Fatalf("unexpected decoding error: %s", err)
+			}
+
+			rErr, ok := xerrors.FindTyped(wErr, (*xerrors.RemoteError)(nil)).(*xerrors.RemoteError)
+			if !ok {
+				t.Fatal("expected to find a RemoteError")
+			}
+
+			if out, expectedOut := rErr.TypeName(), "*xerrors_test.BarError"; out != expectedOut {
+				t.Fatalf("expected type name %q got %q", expectedOut, out)
+			}
+
+			if out, expectedOut := rErr.Error(), "bar-abc"; out != expectedOut {
+				t.Fatalf("expected message %q got %q", expectedOut, out)
+			}
+
+			if !xerrors.Contains(wErr, xerrors.New("some error")) {
+				t.Fatal("expected the decoded error to contain the causal error")
+			}
+
+			if !xerrors.Contains(wErr, &BarError{"abc"}) || !xerrors.Contains(foo(), rErr) {
+				t.Fatal("expected the RemoteError to compare equal to the original")
+			}
+		})
+	}
+
+	decode := func(data string) error {
+		out, err := xerrors.DecodeJSON([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected decoding error: %s", err)
+		}
+		return out
+	}
+
+	if xerrors.Similar(decode(`[{"type":"a.T","message":"x"}]`), decode(`[{"type":"b.U","message":"x"}]`)) {
+		t.Error("expected RemoteErrors with different type names not to be Similar")
+	}
+
+	if !xerrors.Similar(decode(`[{"type":"a.T","message":"x"}]`), decode(`[{"type":"a.T","message":"x"}]`)) {
+		t.Error("expected RemoteErrors with the same type name and message to be Similar")
+	}
+}
+
+func TestDecode_context(t *testing.T) {
+	deadline := time.Now().Add(-time.Second)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	for _, scenario := range encodingScenarios() {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			wErr, err := scenario.decode(scenario.encode(xerrors.ContextErr(ctx, time.Time{})))
+			if err != nil {
+				t.Fatalf("unexpected decoding error: %s", err)
+			}
+
+			if !xerrors.IsDeadline(wErr) {
+				t.Fatal("expected the decoded error to hold context.DeadlineExceeded")
+			}
+
+			cErr, ok := xerrors.FindTyped(wErr, (*xerrors.ContextError)(nil)).(*xerrors.ContextError)
+			if !ok {
+				t.Fatal("expected to find a ContextError")
+			}
+
+			if d, ok := cErr.Deadline(); !ok || !d.Equal(deadline) {
+				t.Fatalf("expected deadline %v, got %v", deadline, d)
+			}
+		})
+	}
+}
+
+func TestDecode_malformed(t *testing.T) {
+	for _, scenario := range encodingScenarios() {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			if _, err := scenario.decode(scenario.malformed); err == nil {
+				t.Fatal("expected a decoding error")
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package xerrors
 
+import "fmt"
+
 // New produces a unwrapped string error without any frame information.
 // Use it to produce untyped sentinel.
 //
@@ -17,3 +19,34 @@ type stringError struct {
 func (err *stringError) Error() string {
 	return err.msg
 }
+
+// Errorf produces an unwrapped error with a message formatted as by fmt.Sprintf, without any frame information.
+// Unlike fmt.Errorf it never wraps, to wrap use Wrap with the output of Errorf as payload.
+// The error implements TemplateError, its template being format.
+func Errorf(format string, args ...interface{}) error {
+	return &formatError{
+		format: format,
+		msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+type formatError struct {
+	format string
+	msg    string
+}
+
+func (err *formatError) Error() string {
+	return err.msg
+}
+
+func (err *formatError) Template() string {
+	return err.format
+}
+
+// TemplateError is an optional interface for errors whose message is produced from a template, such as those of
+// Errorf.
+// Template returns the message with its variable parts left out, i.e. the format string.
+// It is used to identify errors regardless of their variable parts, see Fingerprint.
+type TemplateError interface {
+	Template() string
+}
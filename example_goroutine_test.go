@@ -0,0 +1,37 @@
+package xerrors_test
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// Example_goroutineOpts shows how StackOpts.Goroutine identifies the goroutine each stack was captured in.
+// Unlike Example_wrapWithOpts, stacks from different goroutines can be told apart, and traced back to where their
+// goroutines were started.
+func Example_goroutineOpts() {
+	c := make(chan error, 1)
+
+	go pprof.Do(context.Background(), pprof.Labels("request", "abc"), func(ctx context.Context) {
+		c <- xerrors.WrapWithOpts(nil, xerrors.New("foo"), xerrors.StackOpts{
+			Depth:     10,
+			Goroutine: &xerrors.GoroutineOpts{Name: "handler", Labels: ctx},
+		})
+	})
+
+	err := <-c
+
+	info, _ := xerrors.Find(err, isStackError).(*xerrors.StackError).Goroutine()
+
+	fmt.Println(info.Name)
+	fmt.Println(info.Labels)
+	fmt.Println(info.CreatedBy.Function[strings.LastIndex(info.CreatedBy.Function, "/")+1:])
+
+	// Output:
+	// handler
+	// [[request abc]]
+	// xerrors_test.Example_goroutineOpts
+}
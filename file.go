@@ -0,0 +1,29 @@
+package xerrors
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// IsNotExist reports whether any payload in err indicates a file or directory does not exist, as fs.ErrNotExist.
+// Unlike os.IsNotExist it walks WrappingErrors, and it understands *fs.PathError, *os.LinkError, *os.SyscallError and
+// syscall.Errno payloads along with any payload that wraps fs.ErrNotExist in the manner of the errors package.
+func IsNotExist(err error) bool {
+	return Find(err, func(err error) bool { return errors.Is(err, fs.ErrNotExist) }) != nil
+}
+
+// IsPermission reports whether any payload in err indicates permission was denied, as fs.ErrPermission.
+// It walks WrappingErrors and understands the same payloads as IsNotExist.
+func IsPermission(err error) bool {
+	return Find(err, func(err error) bool { return errors.Is(err, fs.ErrPermission) }) != nil
+}
+
+// IsTimeout reports whether any payload in err indicates a timeout, by having a Timeout() method returning true
+// (directly or wrapped in the manner of the errors package) as syscall.Errno, os.ErrDeadlineExceeded and
+// context.DeadlineExceeded do.
+func IsTimeout(err error) bool {
+	return Find(err, func(err error) bool {
+		var tErr interface{ Timeout() bool }
+		return errors.As(err, &tErr) && tErr.Timeout()
+	}) != nil
+}
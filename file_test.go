@@ -0,0 +1,93 @@
+package xerrors_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestFilePredicates(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		err              error
+		expectNotExist   bool
+		expectPermission bool
+		expectTimeout    bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "unrelated",
+			err:  xerrors.Wrap(xerrors.New("foo"), xerrors.New("bar")),
+		},
+		{
+			name:           "pathError",
+			err:            xerrors.Wrap(&fs.PathError{Op: "open", Path: "foo", Err: syscall.ENOENT}, xerrors.New("bar")),
+			expectNotExist: true,
+		},
+		{
+			name:           "errno",
+			err:            xerrors.Wrap(syscall.ENOENT, xerrors.New("bar")),
+			expectNotExist: true,
+		},
+		{
+			name:           "sentinel",
+			err:            xerrors.Wrap(xerrors.New("foo"), fs.ErrNotExist),
+			expectNotExist: true,
+		},
+		{
+			name:           "stdlibWrapped",
+			err:            xerrors.Wrap(fmt.Errorf("foo: %w", fs.ErrNotExist), xerrors.New("bar")),
+			expectNotExist: true,
+		},
+		{
+			name:             "permission",
+			err:              xerrors.Wrap(&os.LinkError{Op: "link", Old: "a", New: "b", Err: syscall.EACCES}, xerrors.New("bar")),
+			expectPermission: true,
+		},
+		{
+			name:          "errnoTimeout",
+			err:           xerrors.Wrap(&os.SyscallError{Syscall: "connect", Err: syscall.ETIMEDOUT}, xerrors.New("bar")),
+			expectTimeout: true,
+		},
+		{
+			name:          "deadline",
+			err:           xerrors.Wrap(os.ErrDeadlineExceeded, xerrors.New("bar")),
+			expectTimeout: true,
+		},
+		{
+			name:          "contextDeadline",
+			err:           xerrors.ContextErr(deadlineCtx(t), time.Time{}),
+			expectTimeout: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := xerrors.IsNotExist(s.err); out != s.expectNotExist {
+				t.Errorf("expected IsNotExist %t", s.expectNotExist)
+			}
+
+			if out := xerrors.IsPermission(s.err); out != s.expectPermission {
+				t.Errorf("expected IsPermission %t", s.expectPermission)
+			}
+
+			if out := xerrors.IsTimeout(s.err); out != s.expectTimeout {
+				t.Errorf("expected IsTimeout %t", s.expectTimeout)
+			}
+		})
+	}
+}
+
+func deadlineCtx(t *testing.T) context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	t.Cleanup(cancel)
+	return ctx
+}
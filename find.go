@@ -95,6 +95,8 @@ func FindTyped(err error, target error) error {
 
 // Cause retrieves the causal payload error, the first error that originated this chain.
 //
+// Deprecated: Cause returns the last payload whatever its type, use Root, Outermost or Origin instead.
+//
 // [PROPOSAL NOTES]
 //
 // I added this because it is very popular in the current error wrapped implementations, but I think it is not needed,
@@ -110,3 +112,55 @@ func Cause(err error) error {
 
 	return wErr.payload
 }
+
+// Root returns the deepest non-StackError payload in err, the error that originated the chain.
+// If err is not a WrappingError it returns itself, unless it is a StackError. If err is nil, nil is returned.
+// Payloads are not looked into, so the root of a chain ending in a MultiError is the MultiError, and the root of an
+// Opaque boundary is its payload, not the error it hides.
+func Root(err error) error {
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		return Find(err, isNotStackError)
+	}
+
+	var out error
+	for wErr = find(wErr, isNotStackError); wErr != nil; wErr = find(wErr.next, isNotStackError) {
+		out = wErr.payload
+	}
+
+	return out
+}
+
+// Outermost returns the first non-StackError payload in err, the last error to wrap it.
+// Non-WrappingErrors, MultiErrors and Opaque boundaries are handled as in Root.
+func Outermost(err error) error {
+	return Find(err, isNotStackError)
+}
+
+// Origin returns the payload the earliest-captured (deepest) StackError in err was added with, the first non-StackError
+// payload following it, or Root if there are none following.
+// This is the error err originated as within this package, which for errors originating in other packages (such as
+// Wrap(io.EOF, ErrReadFailed)) may be a wrapper of the Root.
+// If err has no StackErrors it is equivalent to Root.
+// Non-WrappingErrors, MultiErrors and Opaque boundaries are handled as in Root.
+func Origin(err error) error {
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		return Find(err, isNotStackError)
+	}
+
+	var stack *WrappingError
+	for ; wErr != nil; wErr = wErr.next {
+		if isStackError(wErr.payload) {
+			stack = wErr
+		}
+	}
+
+	if stack != nil {
+		if following := find(stack.next, isNotStackError); following != nil {
+			return following.payload
+		}
+	}
+
+	return Root(err)
+}
@@ -306,3 +306,105 @@ func TestCause(t *testing.T) {
 		})
 	}
 }
+
+func TestRootOutermostOrigin(t *testing.T) {
+	multi := xerrors.Join(xerrors.New("multi_1"), xerrors.New("multi_2"))
+
+	scenarios := []struct {
+		name              string
+		err               error
+		expectedRoot      error
+		expectedOutermost error
+		expectedOrigin    error
+	}{
+		{
+			name:              "nil",
+			err:               nil,
+			expectedRoot:      nil,
+			expectedOutermost: nil,
+			expectedOrigin:    nil,
+		},
+		{
+			name:              "nonWrapped",
+			err:               xerrors.New("msg"),
+			expectedRoot:      xerrors.New("msg"),
+			expectedOutermost: xerrors.New("msg"),
+			expectedOrigin:    xerrors.New("msg"),
+		},
+		{
+			name:              "nilWrapped",
+			err:               xerrors.Wrap(nil, xerrors.New("msg")),
+			expectedRoot:      xerrors.New("msg"),
+			expectedOutermost: xerrors.New("msg"),
+			expectedOrigin:    xerrors.New("msg"),
+		},
+		{
+			name: "doubleWrapped",
+			err: xerrors.Wrap(
+				xerrors.Wrap(
+					xerrors.New("msg"),
+					xerrors.New("wrapper_1"),
+				),
+				xerrors.New("wrapper_2"),
+			),
+			expectedRoot:      xerrors.New("msg"),
+			expectedOutermost: xerrors.New("wrapper_2"),
+			expectedOrigin:    xerrors.New("wrapper_1"),
+		},
+		{
+			name: "multipleStacks",
+			err: xerrors.WrapWithOpts(
+				xerrors.Wrap(
+					xerrors.Wrap(nil, xerrors.New("msg")),
+					xerrors.New("wrapper_1"),
+				),
+				xerrors.New("wrapper_2"),
+				xerrors.StackOpts{Depth: 1},
+			),
+			expectedRoot:      xerrors.New("msg"),
+			expectedOutermost: xerrors.New("wrapper_2"),
+			expectedOrigin:    xerrors.New("msg"),
+		},
+		{
+			name: "noStacks",
+			err: xerrors.Wrap(
+				xerrors.WrapWithOpts(xerrors.New("msg"), xerrors.New("wrapper_1"), xerrors.StackOpts{}),
+				xerrors.New("wrapper_2"),
+			),
+			expectedRoot:      xerrors.New("msg"),
+			expectedOutermost: xerrors.New("wrapper_2"),
+			expectedOrigin:    xerrors.New("msg"),
+		},
+		{
+			name:              "multi",
+			err:               xerrors.Wrap(multi, xerrors.New("wrapper")),
+			expectedRoot:      multi,
+			expectedOutermost: xerrors.New("wrapper"),
+			expectedOrigin:    xerrors.New("wrapper"),
+		},
+		{
+			name:              "opaque",
+			err:               xerrors.Wrap(xerrors.Opaque(xerrors.New("msg"), xerrors.New("boundary")), xerrors.New("wrapper")),
+			expectedRoot:      xerrors.New("boundary"),
+			expectedOutermost: xerrors.New("wrapper"),
+			expectedOrigin:    xerrors.New("boundary"),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			if out := xerrors.Root(scenario.err); !reflect.DeepEqual(out, scenario.expectedRoot) {
+				t.Errorf("mismatched Root, expected %q got %q", scenario.expectedRoot, out)
+			}
+
+			if out := xerrors.Outermost(scenario.err); !reflect.DeepEqual(out, scenario.expectedOutermost) {
+				t.Errorf("mismatched Outermost, expected %q got %q", scenario.expectedOutermost, out)
+			}
+
+			if out := xerrors.Origin(scenario.err); !reflect.DeepEqual(out, scenario.expectedOrigin) {
+				t.Errorf("mismatched Origin, expected %q got %q", scenario.expectedOrigin, out)
+			}
+		})
+	}
+}
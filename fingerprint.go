@@ -0,0 +1,143 @@
+package xerrors
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+var defaultFingerprintOpts = FingerprintOpts{
+	Templates: true,
+	Frames:    3,
+}
+
+// FingerprintOpts defines what identifies an error in its fingerprint, see FingerprintWithOpts.
+type FingerprintOpts struct {
+	// Templates includes the normalized message of the payloads.
+	// This is their TemplateError template if available, otherwise their Error() output with every word holding a
+	// digit left out.
+	Templates bool
+	// Frames is the number of application frames (not from the standard library) from the causal StackError included,
+	// by function name.
+	Frames uint8
+}
+
+// Fingerprint produces a stable identifier for err, to group and deduplicate errors.
+// It is FingerprintWithOpts with normalized messages and 3 frames.
+func Fingerprint(err error) string {
+	return FingerprintWithOpts(err, defaultFingerprintOpts)
+}
+
+// FingerprintWithOpts produces a stable identifier for err, to group and deduplicate errors.
+// It is a hash of the type names (see TypeName) of all non-StackError payloads along with what opts defines.
+// The output is 16 hexadecimal characters, or the empty string for a nil error.
+//
+// [PROPOSAL NOTES]
+//
+// The fingerprint only depends on type names, messages and function names, and so it does not change across process
+// restarts nor across builds of the same source, regardless of the machine they are built on.
+// The causal StackError is the last one in the chain, it is normally captured where the chain originated.
+func FingerprintWithOpts(err error, opts FingerprintOpts) string {
+	if err == nil {
+		return ""
+	}
+
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		wErr = &WrappingError{payload: err}
+	}
+
+	h := sha256.New()
+
+	var causalStack *StackError
+
+	for ; wErr != nil; wErr = wErr.next {
+		if sErr, ok := wErr.payload.(*StackError); ok {
+			causalStack = sErr
+			continue
+		}
+
+		h.Write([]byte(TypeName(wErr.payload)))
+		h.Write([]byte{0})
+
+		if opts.Templates {
+			h.Write([]byte(template(wErr.payload)))
+			h.Write([]byte{0})
+		}
+	}
+
+	if causalStack != nil && opts.Frames != 0 {
+		n := 0
+		for _, frame := range causalStack.SymbolizedFrames() {
+			if isStdlibFrame(frame.Function, frame.File, mainModule) {
+				continue
+			}
+
+			h.Write([]byte(frame.Function))
+			h.Write([]byte{0})
+
+			if n++; n == int(opts.Frames) {
+				break
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// template is the normalized message of a payload, see FingerprintOpts.Templates
+func template(err error) string {
+	if tErr, ok := err.(TemplateError); ok {
+		return tErr.Template()
+	}
+
+	words := strings.FieldsFunc(err.Error(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		if strings.IndexFunc(word, unicode.IsDigit) != -1 {
+			continue
+		}
+		b.WriteString(word)
+		b.WriteString(" ")
+	}
+
+	return b.String()
+}
+
+type fingerprintFormatter struct {
+	wErr *WrappingError
+}
+
+func (f *fingerprintFormatter) Init(wErr *WrappingError) {
+	f.wErr = wErr
+}
+
+func (f *fingerprintFormatter) Next() error {
+	if f.wErr == nil {
+		return nil
+	}
+
+	// any payload will do, the whole chain is formatted at once
+	return f.wErr.payload
+}
+
+func (f *fingerprintFormatter) CustomFormat(_ error, buf *bytes.Buffer) bool {
+	buf.WriteString(Fingerprint(f.wErr))
+	f.wErr = nil
+	return true
+}
+
+func (f *fingerprintFormatter) Append(w *bytes.Buffer, msg []byte) {
+	w.Write(msg)
+}
+
+// NewFingerprintFormatter provides a formatter that outputs the Fingerprint of the error.
+// It is intended for use in log fields, grouping errors by their fingerprint.
+func NewFingerprintFormatter() Formatter {
+	return &fingerprintFormatter{}
+}
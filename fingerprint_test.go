@@ -0,0 +1,150 @@
+package xerrors_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func fingerprintErrFunc(id int) error {
+	return xerrors.Wrap(nil, xerrors.Errorf("user %d not found", id))
+}
+
+func fingerprintOtherErrFunc(id int) error {
+	return xerrors.Wrap(nil, xerrors.Errorf("user %d not found", id))
+}
+
+func TestFingerprint(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		err1        error
+		err2        error
+		opts        xerrors.FingerprintOpts
+		expectEqual bool
+	}{
+		{
+			name:        "sameOrigin",
+			err1:        fingerprintErrFunc(1),
+			err2:        fingerprintErrFunc(1),
+			opts:        xerrors.FingerprintOpts{Templates: true, Frames: 3},
+			expectEqual: true,
+		},
+		{
+			name:        "templates",
+			err1:        fingerprintErrFunc(1),
+			err2:        fingerprintErrFunc(2),
+			opts:        xerrors.FingerprintOpts{Templates: true, Frames: 3},
+			expectEqual: true,
+		},
+		{
+			name:        "normalizedMessages",
+			err1:        xerrors.WrapWithOpts(nil, xerrors.New("user-1 not found"), xerrors.StackOpts{}),
+			err2:        xerrors.WrapWithOpts(nil, xerrors.New("user-2 not found"), xerrors.StackOpts{}),
+			opts:        xerrors.FingerprintOpts{Templates: true},
+			expectEqual: true,
+		},
+		{
+			name:        "differentMessages",
+			err1:        xerrors.New("foo"),
+			err2:        xerrors.New("bar"),
+			opts:        xerrors.FingerprintOpts{Templates: true},
+			expectEqual: false,
+		},
+		{
+			name:        "differentMessagesNoTemplates",
+			err1:        xerrors.New("foo"),
+			err2:        xerrors.New("bar"),
+			opts:        xerrors.FingerprintOpts{},
+			expectEqual: true,
+		},
+		{
+			name:        "differentTypes",
+			err1:        xerrors.New("bar"),
+			err2:        barError{},
+			opts:        xerrors.FingerprintOpts{},
+			expectEqual: false,
+		},
+		{
+			name:        "differentOrigin",
+			err1:        fingerprintErrFunc(1),
+			err2:        fingerprintOtherErrFunc(1),
+			opts:        xerrors.FingerprintOpts{Templates: true, Frames: 3},
+			expectEqual: false,
+		},
+		{
+			name:        "differentOriginNoFrames",
+			err1:        fingerprintErrFunc(1),
+			err2:        fingerprintOtherErrFunc(1),
+			opts:        xerrors.FingerprintOpts{Templates: true},
+			expectEqual: true,
+		},
+		{
+			name:        "wrapped",
+			err1:        xerrors.Wrap(fingerprintErrFunc(1), xerrors.New("foo")),
+			err2:        fingerprintErrFunc(1),
+			opts:        xerrors.FingerprintOpts{Templates: true, Frames: 3},
+			expectEqual: false,
+		},
+	}
+
+	hexRegexp := regexp.MustCompile("^[0-9a-f]{16}$")
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			fp1 := xerrors.FingerprintWithOpts(scenario.err1, scenario.opts)
+			fp2 := xerrors.FingerprintWithOpts(scenario.err2, scenario.opts)
+
+			if !hexRegexp.MatchString(fp1) || !hexRegexp.MatchString(fp2) {
+				t.Fatalf("expected 16 hexadecimal characters, got %q and %q", fp1, fp2)
+			}
+
+			if (fp1 == fp2) != scenario.expectEqual {
+				t.Fatalf("expected fingerprint equality to be %t, got %q and %q", scenario.expectEqual, fp1, fp2)
+			}
+		})
+	}
+}
+
+func TestFingerprint_nil(t *testing.T) {
+	if out := xerrors.Fingerprint(nil); out != "" {
+		t.Fatalf("expected empty fingerprint, got %q", out)
+	}
+}
+
+func TestNewFingerprintFormatter(t *testing.T) {
+	p := xerrors.NewPrinter(xerrors.NewFingerprintFormatter)
+
+	for _, err := range []error{xerrors.New("foo"), foo()} {
+		if out, expectedOut := p.String(err), xerrors.Fingerprint(err); out != expectedOut {
+			t.Fatalf("expected %q got %q", expectedOut, out)
+		}
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	err := xerrors.Errorf("user %d not found", 1)
+
+	if out, expectedOut := err.Error(), "user 1 not found"; out != expectedOut {
+		t.Fatalf("expected %q got %q", expectedOut, out)
+	}
+
+	if out, expectedOut := err.(xerrors.TemplateError).Template(), "user %d not found"; out != expectedOut {
+		t.Fatalf("expected template %q got %q", expectedOut, out)
+	}
+}
+
+func TestFingerprint_decoded(t *testing.T) {
+	err := xerrors.Wrap(fingerprintErrFunc(1), xerrors.New("foo"))
+
+	wErr, decodeErr := xerrors.DecodeBinary(xerrors.EncodeBinary(err))
+	if decodeErr != nil {
+		t.Fatalf("unexpected decoding error: %s", decodeErr)
+	}
+
+	if fp, expectedFP := xerrors.Fingerprint(wErr), xerrors.Fingerprint(err); fp != expectedFP {
+		t.Fatalf("expected decoded fingerprint %q, got %q", expectedFP, fp)
+	}
+}
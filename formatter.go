@@ -62,6 +62,17 @@ type Formatter interface {
 	Append(*bytes.Buffer, []byte)
 }
 
+// KeyValueError is an optional interface for errors holding structured data, for use by Formatters and encoders.
+// The data is a list of key-value pairs, in a consistent order and with no repeated keys.
+//
+// [PROPOSAL NOTES]
+//
+// This is the KeyValueErrorData standard suggested in Formatter's CustomFormat notes.
+// Values are strings rather than interface{} so they can be transported, see EncodeJSON.
+type KeyValueError interface {
+	KeyValueErrorData() [][2]string
+}
+
 type colonFormatter struct {
 	currentErr *WrappingError
 	firstEntry bool
@@ -0,0 +1,139 @@
+// Package fserr wraps errors returned by os and io/fs with the operation and path that caused them.
+//
+// Use xerrors.IsNotExist, xerrors.IsPermission and xerrors.IsTimeout to check the errors it produces.
+package fserr
+
+import (
+	"io/fs"
+	"os"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// depth is the number of frames in the stacks added, as in xerrors.Wrap
+const depth = 10
+
+// FileError is the payload recording the file system operation and path an error originated in.
+// It is only added to chains that don't already hold this information in a *fs.PathError or *os.LinkError, see Wrap.
+// Do not initialise a FileError directly, use Wrap or the file system functions of this package.
+type FileError struct {
+	op   string
+	path string
+}
+
+// Op is a getter for the operation, i.e. "open".
+func (err *FileError) Op() string {
+	return err.op
+}
+
+// Path is a getter for the path of the file.
+func (err *FileError) Path() string {
+	return err.path
+}
+
+// Error's format is "{op} {path}", as in *fs.PathError.
+func (err *FileError) Error() string {
+	return err.op + " " + err.path
+}
+
+// KeyValueErrorData makes FileError implement xerrors.KeyValueError, with keys "file.op" and "file.path".
+func (err *FileError) KeyValueErrorData() [][2]string {
+	return [][2]string{{"file.op", err.op}, {"file.path", err.path}}
+}
+
+func isPathError(err error) bool {
+	switch err.(type) {
+	case *fs.PathError, *os.LinkError:
+		return true
+	}
+	return false
+}
+
+func isFileError(err error) bool {
+	_, ok := err.(*FileError)
+	return ok
+}
+
+// Wrap wraps err, the result of a file system operation op on path, so the chain holds the operation and path.
+// If err already holds a *fs.PathError or *os.LinkError it is kept as the record of these, otherwise a FileError is
+// added.
+// As xerrors.Wrap, it adds a stack if err is not a WrappingError, and returns nil for a nil err.
+func Wrap(err error, op, path string) error {
+	return wrap(err, op, path)
+}
+
+// wrap is Wrap for the exported functions of this package, with stacks starting at their caller
+func wrap(err error, op, path string) error {
+	if err == nil {
+		return nil
+	}
+
+	var payload error
+	if xerrors.Find(err, isPathError) == nil {
+		payload = &FileError{op: op, path: path}
+	}
+
+	if _, ok := err.(*xerrors.WrappingError); ok {
+		if payload == nil {
+			return err
+		}
+		// as with xerrors.Wrap, if already wrapped not adding a stack
+		return xerrors.Wrap(err, payload)
+	}
+
+	return xerrors.WrapWithOpts(err, payload, xerrors.StackOpts{Skip: 2, Depth: depth})
+}
+
+// FileOf returns the operation and path of the outermost FileError, *fs.PathError or *os.LinkError (using its old
+// path) in err, and false if there are none.
+func FileOf(err error) (op, path string, ok bool) {
+	switch fErr := xerrors.Find(err, func(err error) bool { return isPathError(err) || isFileError(err) }).(type) {
+	case *FileError:
+		return fErr.op, fErr.path, true
+	case *fs.PathError:
+		return fErr.Op, fErr.Path, true
+	case *os.LinkError:
+		return fErr.Op, fErr.Old, true
+	}
+
+	return "", "", false
+}
+
+// Open is os.Open, wrapping any error as Wrap does.
+func Open(name string) (*os.File, error) {
+	f, err := os.Open(name)
+	return f, wrap(err, "open", name)
+}
+
+// Create is os.Create, wrapping any error as Wrap does.
+func Create(name string) (*os.File, error) {
+	f, err := os.Create(name)
+	return f, wrap(err, "create", name)
+}
+
+// ReadFile is os.ReadFile, wrapping any error as Wrap does.
+func ReadFile(name string) ([]byte, error) {
+	b, err := os.ReadFile(name)
+	return b, wrap(err, "read", name)
+}
+
+// WriteFile is os.WriteFile, wrapping any error as Wrap does.
+func WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return wrap(os.WriteFile(name, data, perm), "write", name)
+}
+
+// Stat is os.Stat, wrapping any error as Wrap does.
+func Stat(name string) (fs.FileInfo, error) {
+	info, err := os.Stat(name)
+	return info, wrap(err, "stat", name)
+}
+
+// Remove is os.Remove, wrapping any error as Wrap does.
+func Remove(name string) error {
+	return wrap(os.Remove(name), "remove", name)
+}
+
+// MkdirAll is os.MkdirAll, wrapping any error as Wrap does.
+func MkdirAll(path string, perm fs.FileMode) error {
+	return wrap(os.MkdirAll(path, perm), "mkdir", path)
+}
@@ -0,0 +1,160 @@
+package fserr_test
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+	"github.com/JavierZunzunegui/xerrors/fserr"
+)
+
+func TestFileFunctions(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing", "foo")
+
+	scenarios := []struct {
+		name           string
+		f              func() error
+		expectedOp     string
+		expectNotExist bool
+	}{
+		{
+			name: "open",
+			f: func() error {
+				_, err := fserr.Open(missing)
+				return err
+			},
+			expectedOp:     "open",
+			expectNotExist: true,
+		},
+		{
+			name: "create",
+			f: func() error {
+				_, err := fserr.Create(missing)
+				return err
+			},
+			expectedOp:     "open",
+			expectNotExist: true,
+		},
+		{
+			name: "readFile",
+			f: func() error {
+				_, err := fserr.ReadFile(missing)
+				return err
+			},
+			expectedOp:     "open",
+			expectNotExist: true,
+		},
+		{
+			name:           "writeFile",
+			f:              func() error { return fserr.WriteFile(missing, nil, 0o600) },
+			expectedOp:     "open",
+			expectNotExist: true,
+		},
+		{
+			name: "stat",
+			f: func() error {
+				_, err := fserr.Stat(missing)
+				return err
+			},
+			expectedOp:     "stat",
+			expectNotExist: true,
+		},
+		{
+			name:           "remove",
+			f:              func() error { return fserr.Remove(missing) },
+			expectedOp:     "remove",
+			expectNotExist: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.f()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			// the original *fs.PathError is kept, and no FileError is added
+			if _, ok := xerrors.FindTyped(err, (*fs.PathError)(nil)).(*fs.PathError); !ok {
+				t.Errorf("expected a *fs.PathError, got %q", err)
+			}
+
+			if xerrors.Find(err, isFileError) != nil {
+				t.Errorf("expected no FileError, got %q", err)
+			}
+
+			if op, path, ok := fserr.FileOf(err); !ok || op != s.expectedOp || path != missing {
+				t.Errorf("expected %s %s, got %s %s (%t)", s.expectedOp, missing, op, path, ok)
+			}
+
+			if out := xerrors.IsNotExist(err); out != s.expectNotExist {
+				t.Errorf("expected IsNotExist %t", s.expectNotExist)
+			}
+
+			stackErr, ok := xerrors.Find(err, isStackError).(*xerrors.StackError)
+			if !ok {
+				t.Fatal("expected a StackError")
+			}
+
+			// the stack starts at the caller
+			if frame := stackErr.SymbolizedFrames()[0]; !strings.Contains(frame.Function, ".TestFileFunctions.func") {
+				t.Errorf("expected the stack to start at the test, got %s", frame.Function)
+			}
+		})
+	}
+
+	if err := fserr.MkdirAll(filepath.Join(dir, "a", "b"), 0o700); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if err := fserr.Wrap(nil, "decode", "foo.json"); err != nil {
+		t.Errorf("expected nil, got %q", err)
+	}
+
+	err := fserr.Wrap(xerrors.New("invalid character"), "decode", "foo.json")
+
+	if out, expectedOut := err.Error(), "decode foo.json: invalid character"; out != expectedOut {
+		t.Errorf("expected %q, got %q", expectedOut, out)
+	}
+
+	fErr, ok := xerrors.FindTyped(err, (*fserr.FileError)(nil)).(*fserr.FileError)
+	if !ok {
+		t.Fatal("expected a FileError")
+	}
+
+	if fErr.Op() != "decode" || fErr.Path() != "foo.json" {
+		t.Errorf("unexpected FileError %q", fErr)
+	}
+
+	if op, path, ok := fserr.FileOf(err); !ok || op != "decode" || path != "foo.json" {
+		t.Errorf("expected decode foo.json, got %s %s (%t)", op, path, ok)
+	}
+
+	stackErr, ok := xerrors.Find(err, isStackError).(*xerrors.StackError)
+	if !ok {
+		t.Fatal("expected a StackError")
+	}
+
+	if frame := stackErr.SymbolizedFrames()[0]; !strings.HasSuffix(frame.Function, ".TestWrap") {
+		t.Errorf("expected the stack to start at the test, got %s", frame.Function)
+	}
+
+	if _, _, ok := fserr.FileOf(xerrors.New("foo")); ok {
+		t.Error("expected no file")
+	}
+}
+
+func isFileError(err error) bool {
+	_, ok := err.(*fserr.FileError)
+	return ok
+}
+
+func isStackError(err error) bool {
+	_, ok := err.(*xerrors.StackError)
+	return ok
+}
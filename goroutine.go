@@ -0,0 +1,156 @@
+package xerrors
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxGoroutineStackSize bounds the buffer used to read the goroutine's stack, its "created by" frame is lost beyond it
+const maxGoroutineStackSize = 1 << 20
+
+// GoroutineOpts defines the goroutine metadata recorded along a stack, see StackOpts.Goroutine.
+type GoroutineOpts struct {
+	// Name is a caller-supplied name for the goroutine, i.e. "worker".
+	Name string
+	// Labels is the context holding the pprof labels to record, see runtime/pprof.WithLabels.
+	// No labels are recorded if nil.
+	Labels context.Context
+}
+
+// GoroutineInfo identifies the goroutine a StackError was captured in, see StackError.Goroutine.
+type GoroutineInfo struct {
+	// ID is the goroutine ID, as in panic output.
+	ID uint64
+	// Name is as given in GoroutineOpts.
+	Name string
+	// Labels are the pprof labels of the goroutine, sorted by key.
+	Labels [][2]string
+	// CreatedBy is the go statement that created the goroutine, the zero Frame for the main goroutine.
+	CreatedBy runtime.Frame
+	// ParentID is the ID of the goroutine that created this one, 0 if unknown.
+	ParentID uint64
+}
+
+// String is the header of the goroutine, as "goroutine {id}[ [{name}]][ {{key}={value}, ...}]".
+func (g GoroutineInfo) String() string {
+	var buf bytes.Buffer
+	g.writeHeader(&buf)
+	return buf.String()
+}
+
+func (g GoroutineInfo) writeHeader(buf *bytes.Buffer) {
+	buf.WriteString("goroutine ")
+	buf.WriteString(strconv.FormatUint(g.ID, 10))
+
+	if g.Name != "" {
+		buf.WriteString(" [")
+		buf.WriteString(g.Name)
+		buf.WriteString("]")
+	}
+
+	if len(g.Labels) != 0 {
+		buf.WriteString(" {")
+		for i, kv := range g.Labels {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(kv[0])
+			buf.WriteString("=")
+			buf.WriteString(kv[1])
+		}
+		buf.WriteString("}")
+	}
+}
+
+// writeCreatedBy writes the creating frame, as "created by {function}[ in goroutine {parent}]", if known
+func (g GoroutineInfo) writeCreatedBy(buf *bytes.Buffer) bool {
+	if g.CreatedBy.Function == "" {
+		return false
+	}
+
+	buf.WriteString("created by ")
+	buf.WriteString(g.CreatedBy.Function)
+
+	if g.ParentID != 0 {
+		buf.WriteString(" in goroutine ")
+		buf.WriteString(strconv.FormatUint(g.ParentID, 10))
+	}
+
+	return true
+}
+
+func newGoroutineInfo(opts *GoroutineOpts) *GoroutineInfo {
+	g := &GoroutineInfo{Name: opts.Name}
+
+	if opts.Labels != nil {
+		pprof.ForLabels(opts.Labels, func(key, value string) bool {
+			g.Labels = append(g.Labels, [2]string{key, value})
+			return true
+		})
+		sort.Slice(g.Labels, func(i, j int) bool { return g.Labels[i][0] < g.Labels[j][0] })
+	}
+
+	parseGoroutineStack(g, goroutineStack())
+
+	return g
+}
+
+// goroutineStack is the runtime.Stack output of the current goroutine, growing the buffer until it fits
+func goroutineStack() string {
+	buf := make([]byte, 1024)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) || len(buf) >= maxGoroutineStackSize {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// parseGoroutineStack reads the goroutine ID and creating frame from runtime.Stack output:
+//
+//	goroutine {id} [running]:
+//	...
+//	created by {function} in goroutine {parent}
+//		{file}:{line} +{pc offset}
+func parseGoroutineStack(g *GoroutineInfo, stack string) {
+	if rest, ok := strings.CutPrefix(stack, "goroutine "); ok {
+		if end := strings.IndexByte(rest, ' '); end != -1 {
+			g.ID, _ = strconv.ParseUint(rest[:end], 10, 64)
+		}
+	}
+
+	i := strings.LastIndex(stack, "\ncreated by ")
+	if i == -1 {
+		return
+	}
+
+	lines := strings.SplitN(stack[i+len("\ncreated by "):], "\n", 3)
+
+	function := lines[0]
+	if j := strings.Index(function, " in goroutine "); j != -1 {
+		g.ParentID, _ = strconv.ParseUint(function[j+len(" in goroutine "):], 10, 64)
+		function = function[:j]
+	}
+	g.CreatedBy.Function = function
+
+	if len(lines) < 2 {
+		return
+	}
+
+	location := strings.TrimPrefix(lines[1], "\t")
+	if j := strings.LastIndex(location, " +0x"); j != -1 {
+		location = location[:j]
+	}
+
+	if j := strings.LastIndexByte(location, ':'); j != -1 {
+		if line, err := strconv.Atoi(location[j+1:]); err == nil {
+			g.CreatedBy.File, g.CreatedBy.Line = location[:j], line
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package xerrors
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestParseGoroutineStack(t *testing.T) {
+	scenarios := []struct {
+		name         string
+		stack        string
+		expectedInfo GoroutineInfo
+	}{
+		{
+			name: "main",
+			stack: "goroutine 1 [running]:\n" +
+				"main.main()\n" +
+				"\t/src/main.go:5 +0x1d\n",
+			expectedInfo: GoroutineInfo{ID: 1},
+		},
+		{
+			name: "createdBy",
+			stack: "goroutine 18 [running]:\n" +
+				"main.work()\n" +
+				"\t/src/main.go:12 +0x1d\n" +
+				"created by main.main in goroutine 1\n" +
+				"\t/src/main.go:7 +0x25\n",
+			expectedInfo: GoroutineInfo{
+				ID:        18,
+				CreatedBy: runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 7},
+				ParentID:  1,
+			},
+		},
+		{
+			name: "noParent",
+			stack: "goroutine 18 [running]:\n" +
+				"main.work()\n" +
+				"\t/src/main.go:12\n" +
+				"created by main.main\n" +
+				"\t/src/main.go:7\n",
+			expectedInfo: GoroutineInfo{
+				ID:        18,
+				CreatedBy: runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 7},
+			},
+		},
+		{
+			name: "truncated",
+			stack: "goroutine 18 [running]:\n" +
+				"created by main.main in goroutine 1",
+			expectedInfo: GoroutineInfo{
+				ID:        18,
+				CreatedBy: runtime.Frame{Function: "main.main"},
+				ParentID:  1,
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var info GoroutineInfo
+			parseGoroutineStack(&info, s.stack)
+
+			if !reflect.DeepEqual(info, s.expectedInfo) {
+				t.Errorf("expected %+v, got %+v", s.expectedInfo, info)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package xerrors_test
+
+import (
+	"context"
+	"reflect"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestStackOpts_Goroutine(t *testing.T) {
+	c := make(chan error)
+
+	go pprof.Do(context.Background(), pprof.Labels("job", "42", "attempt", "1"), func(ctx context.Context) {
+		c <- xerrors.WrapWithOpts(nil, xerrors.New("foo"), xerrors.StackOpts{
+			Depth:     1,
+			Goroutine: &xerrors.GoroutineOpts{Name: "worker", Labels: ctx},
+		})
+	})
+
+	err := <-c
+
+	stackErr, ok := xerrors.Find(err, isStackError).(*xerrors.StackError)
+	if !ok {
+		t.Fatal("expected a StackError")
+	}
+
+	info, ok := stackErr.Goroutine()
+	if !ok {
+		t.Fatal("expected goroutine metadata")
+	}
+
+	if info.ID == 0 || info.ParentID == 0 || info.ID == info.ParentID {
+		t.Errorf("expected distinct goroutine and parent IDs, got %d and %d", info.ID, info.ParentID)
+	}
+
+	if info.Name != "worker" {
+		t.Errorf("expected name worker, got %q", info.Name)
+	}
+
+	if expected := [][2]string{{"attempt", "1"}, {"job", "42"}}; !reflect.DeepEqual(info.Labels, expected) {
+		t.Errorf("expected labels %q, got %q", expected, info.Labels)
+	}
+
+	if !strings.HasSuffix(info.CreatedBy.Function, ".TestStackOpts_Goroutine") ||
+		!strings.HasSuffix(info.CreatedBy.File, "/goroutine_test.go") || info.CreatedBy.Line != 17 {
+		t.Errorf("expected to be created by the test, got %+v", info.CreatedBy)
+	}
+
+	header := "goroutine " + strconv.FormatUint(info.ID, 10) + " [worker] {attempt=1, job=42}"
+	if out := info.String(); out != header {
+		t.Errorf("expected header %q, got %q", header, out)
+	}
+
+	createdBy := "created by " + info.CreatedBy.Function + " in goroutine " + strconv.FormatUint(info.ParentID, 10)
+
+	if out := stackErr.Error(); !strings.HasPrefix(out, header+": ") || !strings.HasSuffix(out, " - "+createdBy) {
+		t.Errorf("expected the goroutine header and creating frame, got %q", out)
+	}
+
+	p := xerrors.NewPrinter(xerrors.NewMultilineFormatter(xerrors.MultilineOpts{}))
+	if out := p.String(err); !strings.Contains(out, "\n\n"+header+":\n") || !strings.Contains(out, "\n"+createdBy+"\n\t") {
+		t.Errorf("expected the goroutine header and creating frame, got %q", out)
+	}
+}
+
+func TestStackOpts_noGoroutine(t *testing.T) {
+	stackErr := xerrors.Find(xerrors.Wrap(nil, xerrors.New("foo")), isStackError).(*xerrors.StackError)
+
+	if _, ok := stackErr.Goroutine(); ok {
+		t.Error("expected no goroutine metadata")
+	}
+
+	if out := stackErr.Error(); strings.HasPrefix(out, "goroutine ") {
+		t.Errorf("expected no goroutine header, got %q", out)
+	}
+}
@@ -0,0 +1,81 @@
+package xerrors
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs functions in their own goroutines and collects their errors, in the manner of errgroup.
+// The zero value is ready to use.
+//
+// Errors crossing goroutines lose the stack of where the goroutine was launched, see Example_wrapWithOpts.
+// A Group captures this stack when calling Go, and wraps any error returned by the function with it.
+// The resulting error therefore has the launch stack followed by any stack it already held.
+// Panics in the functions are recovered as by Recover.
+type Group struct {
+	cancel context.CancelCauseFunc
+
+	wg sync.WaitGroup
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// WithContext returns a new Group and a derived context.
+// The context is cancelled the first time a function passed to Go returns an error (with it as cause), or when Wait
+// returns, whichever happens first.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls f in a new goroutine.
+// If f returns an error or panics, the resulting error is wrapped with the stack of the call to Go.
+func (g *Group) Go(f func() error) {
+	launchStack := newStackError(StackOpts{Skip: 1, Depth: defaultDepth})
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		err := safeCall(f)
+		if err == nil {
+			return
+		}
+
+		wErr, ok := err.(*WrappingError)
+		if !ok {
+			wErr = &WrappingError{payload: err}
+		}
+
+		g.add(&WrappingError{payload: launchStack, next: wErr})
+	}()
+}
+
+func (g *Group) add(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.errs) == 0 && g.cancel != nil {
+		g.cancel(err)
+	}
+
+	g.errs = append(g.errs, err)
+}
+
+// Wait blocks until all function calls from Go have returned.
+// It returns nil if none failed, the error if only one did, or otherwise a MultiError of all errors in the order in
+// which they were returned (see Join).
+func (g *Group) Wait() error {
+	g.wg.Wait()
+
+	if g.cancel != nil {
+		g.cancel(context.Canceled)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return Join(g.errs...)
+}
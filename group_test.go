@@ -0,0 +1,138 @@
+package xerrors_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// stackFunctions lists the function names of all StackErrors in err, outermost first
+func stackFunctions(err error) [][]string {
+	var out [][]string
+
+	for wErr, _ := err.(*xerrors.WrappingError); wErr != nil; wErr = wErr.Next() {
+		stackErr, ok := wErr.Payload().(*xerrors.StackError)
+		if !ok {
+			continue
+		}
+
+		var functions []string
+		frames := stackErr.Frames()
+		for frame, more := frames.Next(); ; frame, more = frames.Next() {
+			functions = append(functions, frame.Function)
+			if !more {
+				break
+			}
+		}
+
+		out = append(out, functions)
+	}
+
+	return out
+}
+
+func launchGroupFunc(g *xerrors.Group, f func() error) {
+	g.Go(f)
+}
+
+func TestGroup(t *testing.T) {
+	t.Run("noErrors", func(t *testing.T) {
+		var g xerrors.Group
+		g.Go(func() error { return nil })
+		g.Go(func() error { return nil })
+
+		if err := g.Wait(); err != nil {
+			t.Fatalf("expected nil error, got %q", err)
+		}
+	})
+
+	t.Run("singleError", func(t *testing.T) {
+		var g xerrors.Group
+		g.Go(func() error { return nil })
+		launchGroupFunc(&g, errFunc)
+
+		err := g.Wait()
+		if err == nil {
+			t.Fatal("expected non-nil error")
+		}
+
+		if out, expectedOut := err.Error(), "some error"; out != expectedOut {
+			t.Fatalf("expected %q got %q", expectedOut, out)
+		}
+
+		stacks := stackFunctions(err)
+		if len(stacks) != 2 {
+			t.Fatalf("expected launch and failure stacks, got %d stacks", len(stacks))
+		}
+
+		if !strings.HasSuffix(stacks[0][0], "xerrors_test.launchGroupFunc") {
+			t.Fatalf("expected launch stack to start at launchGroupFunc, got %q", stacks[0][0])
+		}
+
+		if !strings.HasSuffix(stacks[1][0], "xerrors_test.errFunc") {
+			t.Fatalf("expected failure stack to start at errFunc, got %q", stacks[1][0])
+		}
+	})
+
+	t.Run("unwrappedError", func(t *testing.T) {
+		var g xerrors.Group
+		g.Go(func() error { return xerrors.New("foo") })
+
+		err := g.Wait()
+		if !xerrors.Similar(err, xerrors.WrapWithOpts(nil, xerrors.New("foo"), xerrors.StackOpts{})) {
+			t.Fatalf("expected error %q, got %q", "foo", err)
+		}
+
+		if stacks := stackFunctions(err); len(stacks) != 1 {
+			t.Fatalf("expected only the launch stack, got %d stacks", len(stacks))
+		}
+	})
+
+	t.Run("multipleErrors", func(t *testing.T) {
+		var g xerrors.Group
+		g.Go(func() error { return xerrors.New("foo") })
+		g.Go(func() error { panic("bar") })
+
+		mErr, ok := g.Wait().(*xerrors.MultiError)
+		if !ok {
+			t.Fatal("expected a MultiError")
+		}
+
+		errs := mErr.Errors()
+		if len(errs) != 2 {
+			t.Fatalf("expected 2 errors, got %d", len(errs))
+		}
+
+		// the errors are in the order they are returned, which is not deterministic
+		fooErr, panicErr := errs[0], errs[1]
+		if xerrors.FindTyped(fooErr, (*xerrors.PanicError)(nil)) != nil {
+			fooErr, panicErr = panicErr, fooErr
+		}
+
+		if !xerrors.Similar(fooErr, xerrors.WrapWithOpts(nil, xerrors.New("foo"), xerrors.StackOpts{})) {
+			t.Fatalf("expected an error %q, got %q", "foo", fooErr)
+		}
+
+		if xerrors.FindTyped(panicErr, (*xerrors.PanicError)(nil)) == nil {
+			t.Fatalf("expected the other error to be a panic, got %q", panicErr)
+		}
+	})
+
+	t.Run("withContext", func(t *testing.T) {
+		g, ctx := xerrors.WithContext(context.Background())
+		g.Go(func() error { return xerrors.New("foo") })
+		g.Go(func() error { <-ctx.Done(); return nil })
+
+		err := g.Wait()
+
+		if ctx.Err() == nil {
+			t.Fatal("expected context to be cancelled")
+		}
+
+		if cause := context.Cause(ctx); cause != err {
+			t.Fatalf("expected the context cause to be the group error, got %q", cause)
+		}
+	})
+}
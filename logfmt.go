@@ -0,0 +1,150 @@
+package xerrors
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type logfmtFormatter struct {
+	wErr *WrappingError
+}
+
+func (f *logfmtFormatter) Init(wErr *WrappingError) {
+	f.wErr = wErr
+}
+
+func (f *logfmtFormatter) Next() error {
+	if f.wErr == nil {
+		return nil
+	}
+
+	// any payload will do, the whole chain is formatted at once
+	return f.wErr.payload
+}
+
+func (f *logfmtFormatter) CustomFormat(_ error, buf *bytes.Buffer) bool {
+	var (
+		cause       error
+		causalStack *StackError
+		types       []string
+		fields      [][2]string
+	)
+
+	for wErr := f.wErr; wErr != nil; wErr = wErr.next {
+		if sErr, ok := wErr.payload.(*StackError); ok {
+			causalStack = sErr
+			continue
+		}
+
+		cause = wErr.payload
+		types = append(types, TypeName(wErr.payload))
+
+		if kvErr, ok := wErr.payload.(KeyValueError); ok {
+			fields = appendNewFields(fields, kvErr.KeyValueErrorData())
+		}
+	}
+
+	writeLogfmtPair(buf, "err", defaultPrinter.String(f.wErr))
+	f.wErr = nil
+
+	if cause != nil {
+		buf.WriteString(" ")
+		writeLogfmtPair(buf, "err.cause", cause.Error())
+
+		buf.WriteString(" ")
+		writeLogfmtPair(buf, "err.types", strings.Join(types, ","))
+	}
+
+	if causalStack != nil {
+		var stack bytes.Buffer
+		for i, frame := range causalStack.SymbolizedFrames() {
+			if i != 0 {
+				stack.WriteString(";")
+			}
+			formatFrame(frame, causalStack.trim, &stack)
+		}
+
+		buf.WriteString(" ")
+		writeLogfmtPair(buf, "err.stack", stack.String())
+	}
+
+	for _, kv := range fields {
+		buf.WriteString(" ")
+		writeLogfmtPair(buf, "err."+logfmtKey(kv[0]), kv[1])
+	}
+
+	return true
+}
+
+func (f *logfmtFormatter) Append(w *bytes.Buffer, msg []byte) {
+	w.Write(msg)
+}
+
+// appendNewFields appends the fields with keys not already in out, so outer payloads take precedence
+func appendNewFields(out, fields [][2]string) [][2]string {
+	n := len(out)
+
+fieldLoop:
+	for _, kv := range fields {
+		for _, existing := range out[:n] {
+			if existing[0] == kv[0] {
+				continue fieldLoop
+			}
+		}
+		out = append(out, kv)
+	}
+
+	return out
+}
+
+// writeLogfmtPair writes key=value, quoting the value if required
+func writeLogfmtPair(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteString("=")
+
+	if logfmtNeedsQuoting(value) {
+		buf.WriteString(strconv.Quote(value))
+	} else {
+		buf.WriteString(value)
+	}
+}
+
+func logfmtNeedsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for _, r := range s {
+		if r == ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// logfmtKey replaces the characters of key not allowed in logfmt keys with '_'
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// NewLogfmtFormatter provides a formatter that outputs the error in logfmt, for log pipelines:
+//
+//	err="{colon format}" err.cause="{causal message}" err.types="{type},..." err.stack="{frame};..." err.{key}={value} ...
+//
+// Types are as by TypeName, outermost first.
+// The stack is that of the causal (last) StackError, if any, with frames formatted as in StackError's Error().
+// The remaining fields come from payloads implementing KeyValueError, with outer payloads taking precedence on
+// repeated keys.
+// Values are quoted as Go strings when they are empty or hold spaces, '=', quotes or non-printable characters.
+func NewLogfmtFormatter() Formatter {
+	return &logfmtFormatter{}
+}
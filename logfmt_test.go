@@ -0,0 +1,62 @@
+package xerrors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestNewLogfmtFormatter(t *testing.T) {
+	p := xerrors.NewPrinter(xerrors.NewLogfmtFormatter)
+
+	scenarios := []struct {
+		name           string
+		err            func(*testing.T) error
+		expectedOutput string
+	}{
+		{
+			name:           "unwrapped",
+			err:            func(*testing.T) error { return xerrors.New("foo") },
+			expectedOutput: `err=foo err.cause=foo err.types=xerrors.string`,
+		},
+		{
+			name: "quoted",
+			err: func(*testing.T) error {
+				return xerrors.WrapWithOpts(xerrors.New(`bar "baz"`), xerrors.New("foo=1"), xerrors.StackOpts{})
+			},
+			expectedOutput: `err="foo=1: bar \"baz\"" err.cause="bar \"baz\"" err.types=xerrors.string,xerrors.string`,
+		},
+		{
+			name: "escaped",
+			err: func(*testing.T) error {
+				return xerrors.New("foo\nbar")
+			},
+			expectedOutput: `err="foo\nbar" err.cause="foo\nbar" err.types=xerrors.string`,
+		},
+		{
+			name:           "stack",
+			err:            decodedStackErr,
+			expectedOutput: `err="foo: bar" err.cause=bar err.types=xerrors.string,xerrors.string err.stack=github.com/foo/bar.Baz:/src/bar/baz.go:12;net/http.HandlerFunc.ServeHTTP:/go/src/net/http/server.go:2166`,
+		},
+		{
+			name: "fields",
+			err: func(*testing.T) error {
+				return xerrors.WrapWithOpts(
+					xerrors.WrapWithOpts(xerrors.New("foo"), xerrors.RetryAfter(time.Second), xerrors.StackOpts{}),
+					xerrors.Permanent,
+					xerrors.StackOpts{},
+				)
+			},
+			expectedOutput: `err="permanent: retry after 1s: foo" err.cause=foo err.types=xerrors.retry,xerrors.retry,xerrors.string err.retryable=false err.after=1s`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := p.String(s.err(t)); out != s.expectedOutput {
+				t.Errorf("expected %s, got %s", s.expectedOutput, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package xerrors
+
+import "bytes"
+
+// MultiError is the payload holding several independent errors, such as those of a Group.
+// Do not initialise a MultiError directly, use Join.
+//
+// [PROPOSAL NOTES]
+//
+// The errors are not part of the wrapping chain: Find, FindTyped, Similar, Contains and Formatters treat a MultiError
+// as a single payload.
+// Inspecting the individual errors is an explicit decision, done via Errors.
+type MultiError struct {
+	errs []error
+}
+
+// Join produces a MultiError out of the non-nil errors provided.
+// If there are no non-nil errors it returns nil, and if there is exactly one it returns it unchanged.
+func Join(errs ...error) error {
+	var n int
+	var last error
+	for _, err := range errs {
+		if err != nil {
+			n++
+			last = err
+		}
+	}
+
+	switch n {
+	case 0:
+		return nil
+	case 1:
+		return last
+	}
+
+	mErr := &MultiError{errs: make([]error, 0, n)}
+	for _, err := range errs {
+		if err != nil {
+			mErr.errs = append(mErr.errs, err)
+		}
+	}
+
+	return mErr
+}
+
+// Errors is a getter for the errors held.
+// It has at least two elements, none of them nil.
+func (err *MultiError) Errors() []error {
+	return err.errs
+}
+
+// ErrorToBuffer makes MultiError implement BufferError.
+// The format is "[{errs[0]}; {errs[1]}; ... ; {errs[N-1]}]", each of the errors printed via Error().
+func (err *MultiError) ErrorToBuffer(buf *bytes.Buffer) {
+	buf.WriteString("[")
+
+	for i, e := range err.errs {
+		if i != 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(e.Error())
+	}
+
+	buf.WriteString("]")
+}
+
+// Error is the string format of MultiError.ErrorToBuffer
+func (err *MultiError) Error() string {
+	return BufferErrorToString(err)
+}
@@ -0,0 +1,82 @@
+package xerrors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestJoin(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		errs           []error
+		expectedErrors []error
+		expectedOutput string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "allNil",
+			errs: []error{nil, nil},
+		},
+		{
+			name:           "single",
+			errs:           []error{nil, xerrors.New("foo"), nil},
+			expectedOutput: "foo",
+		},
+		{
+			name:           "multiple",
+			errs:           []error{xerrors.New("foo"), nil, xerrors.Wrap(xerrors.New("bar"), xerrors.New("wrapper"))},
+			expectedErrors: []error{xerrors.New("foo"), xerrors.Wrap(xerrors.New("bar"), xerrors.New("wrapper"))},
+			expectedOutput: "[foo; wrapper: bar]",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			err := xerrors.Join(scenario.errs...)
+
+			if scenario.expectedOutput == "" {
+				if err != nil {
+					t.Fatalf("expected nil error, got %q", err)
+				}
+				return
+			}
+
+			if out := err.Error(); out != scenario.expectedOutput {
+				t.Fatalf("expected %q got %q", scenario.expectedOutput, out)
+			}
+
+			mErr, ok := err.(*xerrors.MultiError)
+			if scenario.expectedErrors == nil {
+				if ok {
+					t.Fatal("expected a single error not to be joined")
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatalf("expected a MultiError, got %T", err)
+			}
+
+			errs := mErr.Errors()
+			if len(errs) != len(scenario.expectedErrors) {
+				t.Fatalf("expected %d errors, got %d", len(scenario.expectedErrors), len(errs))
+			}
+
+			for i := range errs {
+				if !xerrors.Similar(errs[i], scenario.expectedErrors[i]) {
+					t.Fatalf("expected error %q got %q, at index %d", scenario.expectedErrors[i], errs[i], i)
+				}
+			}
+
+			if !reflect.DeepEqual(xerrors.FindTyped(xerrors.Wrap(err, xerrors.New("group")), mErr), mErr) {
+				t.Fatal("expected the MultiError to be found as a payload")
+			}
+		})
+	}
+}
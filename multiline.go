@@ -0,0 +1,236 @@
+package xerrors
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// ANSI escape sequences used by colored Formatters
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// MultilineOpts defines the output of the Formatters of NewMultilineFormatter.
+type MultilineOpts struct {
+	// Color enables ANSI colors: the outermost message in bold, the causal one in red, type names dimmed, and
+	// application frames highlighted while standard library frames are dimmed.
+	Color bool
+	// SourceLines is the number of lines of source printed before and after the line of each frame, with the line
+	// itself marked, for frames whose file can be read.
+	// Source is not printed if it is 0, the default.
+	// Files are read once and cached, up to a bounded number of them.
+	SourceLines int
+	// Trim shortens the file paths of frames, see PathTrim.
+	// If 0, the trimming of each StackError applies.
+	Trim PathTrim
+}
+
+type multilineFormatter struct {
+	opts MultilineOpts
+
+	payloads []error
+	stacks   []*StackError
+
+	// index of the next payload, and then of the next stack past len(payloads)
+	next    int
+	current error
+}
+
+func (f *multilineFormatter) Init(wErr *WrappingError) {
+	f.payloads = f.payloads[:0]
+	f.stacks = f.stacks[:0]
+
+	for ; wErr != nil; wErr = wErr.next {
+		if sErr, ok := wErr.payload.(*StackError); ok {
+			f.stacks = append(f.stacks, sErr)
+			continue
+		}
+		f.payloads = append(f.payloads, wErr.payload)
+	}
+
+	f.next = 0
+	f.current = nil
+}
+
+func (f *multilineFormatter) Next() error {
+	switch {
+	case f.next < len(f.payloads):
+		f.current = f.payloads[f.next]
+	case f.next < len(f.payloads)+len(f.stacks):
+		f.current = f.stacks[f.next-len(f.payloads)]
+	default:
+		f.current = nil
+		return nil
+	}
+
+	f.next++
+
+	return f.current
+}
+
+func (f *multilineFormatter) CustomFormat(err error, buf *bytes.Buffer) bool {
+	sErr, ok := err.(*StackError)
+	if !ok {
+		return false
+	}
+
+	trim := f.opts.Trim
+	if trim == 0 {
+		trim = sErr.trim
+	}
+
+	if sErr.goroutine != nil {
+		sErr.goroutine.writeHeader(buf)
+		buf.WriteString(":\n")
+	}
+
+	for i, frame := range sErr.SymbolizedFrames() {
+		if i != 0 {
+			buf.WriteString("\n")
+		}
+		f.formatFrame(frame, trim, buf)
+	}
+
+	if sErr.goroutine != nil && sErr.goroutine.CreatedBy.Function != "" {
+		// as in panics, the creating frame comes last
+		buf.WriteString("\n")
+		sErr.goroutine.writeCreatedBy(buf)
+		buf.WriteString("\n\t")
+		buf.WriteString(trimPath(sErr.goroutine.CreatedBy.Function, sErr.goroutine.CreatedBy.File, trim, mainModule, mainPackage))
+		buf.WriteString(":")
+		buf.WriteString(strconv.Itoa(sErr.goroutine.CreatedBy.Line))
+	}
+
+	return true
+}
+
+// formatFrame writes the frame as in panics, "{function}\n\t{file}:{line}"
+func (f *multilineFormatter) formatFrame(frame runtime.Frame, trim PathTrim, buf *bytes.Buffer) {
+	var functionColor, fileColor string
+	if f.opts.Color {
+		if isStdlibFrame(frame.Function, frame.File, mainModule) {
+			functionColor, fileColor = ansiDim, ansiDim
+		} else {
+			functionColor = ansiCyan
+		}
+	}
+
+	f.colored(buf, functionColor, frame.Function)
+	buf.WriteString("\n\t")
+	f.colored(buf, fileColor, trimPath(frame.Function, frame.File, trim, mainModule, mainPackage)+":"+strconv.Itoa(frame.Line))
+
+	if f.opts.SourceLines > 0 {
+		var highlight string
+		if f.opts.Color {
+			highlight = ansiBold
+		}
+		writeSource(buf, defaultSourceCache, frame.File, frame.Line, f.opts.SourceLines, "\t", highlight)
+	}
+}
+
+func (f *multilineFormatter) Append(w *bytes.Buffer, msg []byte) {
+	i := f.next - 1
+
+	if i >= len(f.payloads) {
+		// stacks are separated by an empty line
+		w.WriteString("\n\n")
+		w.Write(msg)
+		return
+	}
+
+	if i != 0 {
+		w.WriteString("\n")
+	}
+
+	var color, typeColor string
+	if f.opts.Color {
+		typeColor = ansiDim
+		if i == 0 {
+			color = ansiBold
+		}
+		if i == len(f.payloads)-1 {
+			color += ansiRed
+		}
+	}
+
+	f.colored(w, color, string(msg))
+
+	w.WriteString(" ")
+	f.colored(w, typeColor, "["+TypeName(f.current)+"]")
+}
+
+// colored writes s in the given color, or as is if the color is empty
+func (f *multilineFormatter) colored(w *bytes.Buffer, color, s string) {
+	if color == "" {
+		w.WriteString(s)
+		return
+	}
+
+	w.WriteString(color)
+	w.WriteString(s)
+	w.WriteString(ansiReset)
+}
+
+// NewMultilineFormatter provides a formatter factory for detailed, human-readable output.
+// Every payload is printed in its own line followed by its type as by TypeName, outermost first, followed by all
+// StackErrors in the style of panics, each preceded by an empty line:
+//
+//	{message} [{type}]
+//	...
+//
+//	{function}
+//		{file}:{line}
+//	...
+//
+// StackErrors with goroutine metadata (see StackOpts.Goroutine) start with its header and end with its creating frame:
+//
+//	goroutine {id} [{name}] {{key}={value}, ...}:
+//	{function}
+//		{file}:{line}
+//	...
+//	created by {function} in goroutine {parent}
+//		{file}:{line}
+//
+// See MultilineOpts for the options, including printing the source around each frame.
+func NewMultilineFormatter(opts MultilineOpts) func() Formatter {
+	return func() Formatter {
+		return &multilineFormatter{opts: opts}
+	}
+}
+
+// TerminalFormatter provides a NewMultilineFormatter factory for output written to w, with colors enabled only if w
+// is a terminal and the NO_COLOR environment variable is unset or empty (see https://no-color.org).
+func TerminalFormatter(w io.Writer) func() Formatter {
+	return NewMultilineFormatter(MultilineOpts{Color: colorTerminal(w)})
+}
+
+func colorTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device, such as a terminal.
+// It relies on Stat rather than on ioctls, and so needs no cgo nor system specific code.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,178 @@
+package xerrors_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// decodedStackErr is "foo: bar" with a stack of an application frame over a standard library one
+func decodedStackErr(t *testing.T) error {
+	t.Helper()
+
+	wErr, err := xerrors.DecodeJSON([]byte(`[
+		{"type":"xerrors.string","message":"foo"},
+		{"type":"xerrors.string","message":"bar"},
+		{"type":"xerrors.stack","frames":[
+			{"function":"github.com/foo/bar.Baz","file":"/src/bar/baz.go","line":12},
+			{"function":"net/http.HandlerFunc.ServeHTTP","file":"/go/src/net/http/server.go","line":2166}
+		]}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected decoding error: %s", err)
+	}
+
+	return wErr
+}
+
+func TestNewMultilineFormatter(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		opts           xerrors.MultilineOpts
+		err            func(*testing.T) error
+		expectedOutput string
+	}{
+		{
+			name:           "unwrapped",
+			err:            func(*testing.T) error { return xerrors.New("foo") },
+			expectedOutput: "foo [xerrors.string]",
+		},
+		{
+			name:           "unwrappedColor",
+			opts:           xerrors.MultilineOpts{Color: true},
+			err:            func(*testing.T) error { return xerrors.New("foo") },
+			expectedOutput: "\x1b[1m\x1b[31mfoo\x1b[0m \x1b[2m[xerrors.string]\x1b[0m",
+		},
+		{
+			name: "stack",
+			err:  decodedStackErr,
+			expectedOutput: "foo [xerrors.string]\n" +
+				"bar [xerrors.string]\n" +
+				"\n" +
+				"github.com/foo/bar.Baz\n" +
+				"\t/src/bar/baz.go:12\n" +
+				"net/http.HandlerFunc.ServeHTTP\n" +
+				"\t/go/src/net/http/server.go:2166",
+		},
+		{
+			name: "stackColor",
+			opts: xerrors.MultilineOpts{Color: true},
+			err:  decodedStackErr,
+			expectedOutput: "\x1b[1mfoo\x1b[0m \x1b[2m[xerrors.string]\x1b[0m\n" +
+				"\x1b[31mbar\x1b[0m \x1b[2m[xerrors.string]\x1b[0m\n" +
+				"\n" +
+				"\x1b[36mgithub.com/foo/bar.Baz\x1b[0m\n" +
+				"\t/src/bar/baz.go:12\n" +
+				"\x1b[2mnet/http.HandlerFunc.ServeHTTP\x1b[0m\n" +
+				"\t\x1b[2m/go/src/net/http/server.go:2166\x1b[0m",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := xerrors.NewPrinter(xerrors.NewMultilineFormatter(s.opts))
+
+			if out := p.String(s.err(t)); out != s.expectedOutput {
+				t.Errorf("expected %q, got %q", s.expectedOutput, out)
+			}
+		})
+	}
+}
+
+func TestTerminalFormatter(t *testing.T) {
+	err := xerrors.New("foo")
+
+	t.Run("notTerminal", func(t *testing.T) {
+		p := xerrors.NewPrinter(xerrors.TerminalFormatter(&bytes.Buffer{}))
+
+		if out, expectedOut := p.String(err), "foo [xerrors.string]"; out != expectedOut {
+			t.Errorf("expected %q, got %q", expectedOut, out)
+		}
+	})
+
+	t.Run("NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+
+		p := xerrors.NewPrinter(xerrors.TerminalFormatter(os.Stdout))
+
+		if out, expectedOut := p.String(err), "foo [xerrors.string]"; out != expectedOut {
+			t.Errorf("expected %q, got %q", expectedOut, out)
+		}
+	})
+}
+
+func TestNewMultilineFormatter_sourceLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.go")
+	if err := os.WriteFile(path, []byte("package foo\n\nfunc Foo() error {\n\treturn bar()\n}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err, decodeErr := xerrors.DecodeJSON([]byte(`[
+		{"type":"xerrors.string","message":"foo"},
+		{"type":"xerrors.stack","frames":[
+			{"function":"github.com/foo/foo.Foo","file":` + strconv.Quote(path) + `,"line":4},
+			{"function":"github.com/foo/foo.Bar","file":"/does/not/exist.go","line":1}
+		]}
+	]`))
+	if decodeErr != nil {
+		t.Fatalf("unexpected decoding error: %s", decodeErr)
+	}
+
+	scenarios := []struct {
+		name           string
+		opts           xerrors.MultilineOpts
+		expectedOutput string
+	}{
+		{
+			name: "disabled",
+			expectedOutput: "foo [xerrors.string]\n" +
+				"\n" +
+				"github.com/foo/foo.Foo\n" +
+				"\t" + path + ":4\n" +
+				"github.com/foo/foo.Bar\n" +
+				"\t/does/not/exist.go:1",
+		},
+		{
+			name: "one",
+			opts: xerrors.MultilineOpts{SourceLines: 1},
+			expectedOutput: "foo [xerrors.string]\n" +
+				"\n" +
+				"github.com/foo/foo.Foo\n" +
+				"\t" + path + ":4\n" +
+				"\t  3 | func Foo() error {\n" +
+				"\t> 4 | \treturn bar()\n" +
+				"\t  5 | }\n" +
+				"github.com/foo/foo.Bar\n" +
+				"\t/does/not/exist.go:1",
+		},
+		{
+			name: "boundsAndColor",
+			opts: xerrors.MultilineOpts{SourceLines: 3, Color: true},
+			expectedOutput: "\x1b[1m\x1b[31mfoo\x1b[0m \x1b[2m[xerrors.string]\x1b[0m\n" +
+				"\n" +
+				"\x1b[36mgithub.com/foo/foo.Foo\x1b[0m\n" +
+				"\t" + path + ":4\n" +
+				"\t  1 | package foo\n" +
+				"\t  2 | \n" +
+				"\t  3 | func Foo() error {\n" +
+				"\t\x1b[1m> 4 | \treturn bar()\x1b[0m\n" +
+				"\t  5 | }\n" +
+				"\x1b[36mgithub.com/foo/foo.Bar\x1b[0m\n" +
+				"\t/does/not/exist.go:1",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := xerrors.NewPrinter(xerrors.NewMultilineFormatter(s.opts))
+
+			if out := p.String(err); out != s.expectedOutput {
+				t.Errorf("expected %q, got %q", s.expectedOutput, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package xerrors
+
+import (
+	"bytes"
+)
+
+// Opaque produces an error with payload as its only visible error, hiding err behind it.
+// It is intended for API boundaries, where callers should see a single error and not couple to the internal ones:
+//
+//	if err != nil {
+//		return xerrors.Opaque(err, ErrUnavailable)
+//	}
+//
+// Find, FindTyped, Contains, errors.As, Error() and all Formatters only see the payload (and anything later wrapping
+// it), while Internal and the formatters of NewInternalFormatter still reach err for diagnostics.
+// errors.As and errors.Is never reach err as WrappingError does not implement Unwrap.
+// The hidden chain is not encoded by EncodeJSON or EncodeBinary.
+//
+// If err is not a WrappingError a stack is added to it, starting from Opaque, Opaque not included.
+// If err is nil the output is nil, and if payload is nil it is Wrap(err, nil).
+func Opaque(err, payload error) error {
+	if err == nil {
+		return nil
+	}
+
+	if payload == nil {
+		if _, ok := err.(*WrappingError); ok {
+			return err
+		}
+		return frameWrap(&WrappingError{payload: err}, defaultStackOpts)
+	}
+
+	internal, ok := err.(*WrappingError)
+	if !ok {
+		internal = frameWrap(&WrappingError{payload: err}, defaultStackOpts)
+	}
+
+	pErr, ok := payload.(*WrappingError)
+	if !ok {
+		return &WrappingError{payload: payload, internal: internal}
+	}
+
+	// avoid doing this, payload should not be WrappingError as it causes many allocations
+	head := &WrappingError{}
+	last := head
+
+	for ; pErr != nil; pErr = pErr.next {
+		last.next = &WrappingError{payload: pErr.payload, internal: pErr.internal}
+		last = last.next
+	}
+
+	last.internal = internal
+
+	return head.next
+}
+
+// Internal returns the error hidden by the outermost Opaque boundary in err, or nil if there is none.
+func Internal(err error) error {
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		return nil
+	}
+
+	for ; wErr != nil; wErr = wErr.next {
+		if wErr.internal != nil {
+			return wErr.internal
+		}
+	}
+
+	return nil
+}
+
+// withInternal returns the chain with the errors hidden by any Opaque boundaries inserted after them, or wErr itself
+// if there are none.
+func withInternal(wErr *WrappingError) *WrappingError {
+	current := wErr
+	for ; current != nil && current.internal == nil; current = current.next {
+	}
+
+	if current == nil {
+		return wErr
+	}
+
+	head := &WrappingError{}
+	last := head
+
+	for ; wErr != nil; wErr = wErr.next {
+		last.next = &WrappingError{payload: wErr.payload}
+		last = last.next
+
+		if wErr.internal == nil {
+			continue
+		}
+
+		for iErr := withInternal(wErr.internal); iErr != nil; iErr = iErr.next {
+			last.next = &WrappingError{payload: iErr.payload}
+			last = last.next
+		}
+	}
+
+	return head.next
+}
+
+type internalFormatter struct {
+	f Formatter
+}
+
+func (f *internalFormatter) Init(wErr *WrappingError) {
+	f.f.Init(withInternal(wErr))
+}
+
+func (f *internalFormatter) Next() error {
+	return f.f.Next()
+}
+
+func (f *internalFormatter) CustomFormat(err error, buf *bytes.Buffer) bool {
+	return f.f.CustomFormat(err, buf)
+}
+
+func (f *internalFormatter) Append(w *bytes.Buffer, msg []byte) {
+	f.f.Append(w, msg)
+}
+
+// NewInternalFormatter provides a formatter factory that formats errors as the formatters of fFactory do, except the
+// errors hidden by Opaque boundaries are included, each right after its boundary payload.
+// Use it for logs and other diagnostics, never for output visible to the callers the boundary hides the errors from.
+func NewInternalFormatter(fFactory func() Formatter) func() Formatter {
+	return func() Formatter {
+		return &internalFormatter{f: fFactory()}
+	}
+}
@@ -0,0 +1,137 @@
+package xerrors_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+type secretError struct{}
+
+func (secretError) Error() string { return "connection refused to 10.0.0.1" }
+
+var errUnavailable = xerrors.New("service unavailable")
+
+func TestOpaque(t *testing.T) {
+	if err := xerrors.Opaque(nil, errUnavailable); err != nil {
+		t.Errorf("expected nil, got %q", err)
+	}
+
+	scenarios := []struct {
+		name           string
+		err            error
+		expectedOutput string
+	}{
+		{
+			name:           "unwrapped",
+			err:            xerrors.Opaque(secretError{}, errUnavailable),
+			expectedOutput: "service unavailable",
+		},
+		{
+			name:           "wrapped",
+			err:            xerrors.Opaque(xerrors.Wrap(secretError{}, xerrors.New("dial")), errUnavailable),
+			expectedOutput: "service unavailable",
+		},
+		{
+			name:           "wrappedAfter",
+			err:            xerrors.Wrap(xerrors.Opaque(secretError{}, errUnavailable), xerrors.New("get user")),
+			expectedOutput: "get user: service unavailable",
+		},
+		{
+			name:           "wrappedPayload", // anti-pattern
+			err:            xerrors.Opaque(secretError{}, xerrors.Wrap(errUnavailable, xerrors.New("get user"))),
+			expectedOutput: "get user: service unavailable",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := s.err.Error(); out != s.expectedOutput {
+				t.Errorf("expected %q, got %q", s.expectedOutput, out)
+			}
+
+			if xerrors.FindTyped(s.err, secretError{}) != nil {
+				t.Error("expected FindTyped not to reach the internal error")
+			}
+
+			if xerrors.Find(s.err, func(err error) bool { return err.Error() == secretError{}.Error() }) != nil {
+				t.Error("expected Find not to reach the internal error")
+			}
+
+			if xerrors.Contains(s.err, secretError{}) {
+				t.Error("expected Contains not to reach the internal error")
+			}
+
+			if !xerrors.Contains(s.err, errUnavailable) {
+				t.Error("expected Contains to reach the boundary payload")
+			}
+
+			var sErr secretError
+			if errors.As(s.err, &sErr) {
+				t.Error("expected errors.As not to reach the internal error")
+			}
+
+			internal := xerrors.Internal(s.err)
+			if xerrors.FindTyped(internal, secretError{}) == nil {
+				t.Errorf("expected Internal to reach the internal error, got %v", internal)
+			}
+
+			// the hidden chain survives chain manipulation
+			if out := xerrors.Internal(xerrors.StripStacks(s.err)); out != internal {
+				t.Errorf("expected StripStacks to keep the internal error %q, got %v", internal, out)
+			}
+
+			internalOut := xerrors.NewPrinter(xerrors.NewInternalFormatter(xerrors.NewColonFormatter)).String(s.err)
+			if !strings.HasPrefix(internalOut, s.expectedOutput+": ") ||
+				!strings.HasSuffix(internalOut, secretError{}.Error()) {
+				t.Errorf("expected internal output to extend %q with the internal error, got %q", s.expectedOutput, internalOut)
+			}
+
+			if out := xerrors.NewPrinter(xerrors.NewColonFormatter).String(s.err); out != s.expectedOutput {
+				t.Errorf("expected printer output %q, got %q", s.expectedOutput, out)
+			}
+		})
+	}
+}
+
+func TestOpaqueStack(t *testing.T) {
+	err := xerrors.Opaque(secretError{}, errUnavailable)
+
+	if xerrors.Find(err, isStackError) != nil {
+		t.Error("expected no visible stack")
+	}
+
+	stackErr, ok := xerrors.Find(xerrors.Internal(err), isStackError).(*xerrors.StackError)
+	if !ok {
+		t.Fatal("expected an internal stack")
+	}
+
+	if frame := stackErr.SymbolizedFrames()[0]; !strings.HasSuffix(frame.Function, ".TestOpaqueStack") {
+		t.Errorf("expected the stack to start at the test, got %s", frame.Function)
+	}
+}
+
+func TestInternal(t *testing.T) {
+	if out := xerrors.Internal(nil); out != nil {
+		t.Errorf("expected nil, got %q", out)
+	}
+
+	if out := xerrors.Internal(xerrors.Wrap(nil, errUnavailable)); out != nil {
+		t.Errorf("expected nil, got %q", out)
+	}
+
+	// nested boundaries: the outermost one hides the inner boundary, which in turn hides the cause
+	inner := xerrors.Opaque(secretError{}, xerrors.New("dial failed"))
+	outer := xerrors.Opaque(inner, errUnavailable)
+
+	if out, expectedOut := xerrors.Internal(outer).Error(), "dial failed"; out != expectedOut {
+		t.Errorf("expected %q, got %q", expectedOut, out)
+	}
+
+	out := xerrors.NewPrinter(xerrors.NewInternalFormatter(xerrors.NewColonFormatter)).String(outer)
+	if expectedOut := "service unavailable: dial failed: " + (secretError{}).Error(); out != expectedOut {
+		t.Errorf("expected %q, got %q", expectedOut, out)
+	}
+}
@@ -0,0 +1,107 @@
+// Package otelerr exports wrapped errors as OpenTelemetry exceptions, following the semantic conventions for
+// exception attributes and span events.
+//
+// It depends on a minimal local Span interface rather than on OpenTelemetry itself, adapting a trace.Span to it is
+// a matter of converting Attributes to attribute.KeyValues.
+package otelerr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// Semantic convention keys and event name for exceptions.
+const (
+	KeyExceptionType       = "exception.type"
+	KeyExceptionMessage    = "exception.message"
+	KeyExceptionStacktrace = "exception.stacktrace"
+
+	EventException = "exception"
+)
+
+// Attribute is a string-valued span attribute.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Span is the subset of an OpenTelemetry span RecordError needs.
+type Span interface {
+	AddEvent(name string, attributes []Attribute)
+}
+
+// Attributes returns the exception attributes of err, or nil for a nil error:
+//   - exception.type is the xerrors.TypeName of the causal payload, see xerrors.Root.
+//   - exception.message is err.Error(), the colon format.
+//   - exception.stacktrace holds the frames of all StackErrors, outermost first, see Stacktrace.
+//     It is omitted if there are none.
+func Attributes(err error) []Attribute {
+	if err == nil {
+		return nil
+	}
+
+	out := []Attribute{
+		{Key: KeyExceptionType, Value: xerrors.TypeName(xerrors.Root(err))},
+		{Key: KeyExceptionMessage, Value: err.Error()},
+	}
+
+	if stacktrace := Stacktrace(err); stacktrace != "" {
+		out = append(out, Attribute{Key: KeyExceptionStacktrace, Value: stacktrace})
+	}
+
+	return out
+}
+
+// RecordError adds an "exception" event with the Attributes of err to span.
+// It does nothing for a nil error.
+func RecordError(span Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.AddEvent(EventException, Attributes(err))
+}
+
+// Stacktrace formats the frames of all StackErrors in err in the style of Go panics, outermost first and separated
+// by an empty line:
+//
+//	{function}
+//		{file}:{line}
+//	...
+//
+// It is empty if err holds no StackErrors.
+func Stacktrace(err error) string {
+	wErr, ok := err.(*xerrors.WrappingError)
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+
+	for ; wErr != nil; wErr = wErr.Next() {
+		sErr, ok := wErr.Payload().(*xerrors.StackError)
+		if !ok {
+			continue
+		}
+
+		if b.Len() != 0 {
+			b.WriteString("\n\n")
+		}
+
+		for i, frame := range sErr.SymbolizedFrames() {
+			if i != 0 {
+				b.WriteString("\n")
+			}
+
+			b.WriteString(frame.Function)
+			b.WriteString("\n\t")
+			b.WriteString(xerrors.TrimPath(frame, sErr.PathTrim()))
+			b.WriteString(":")
+			b.WriteString(strconv.Itoa(frame.Line))
+		}
+	}
+
+	return b.String()
+}
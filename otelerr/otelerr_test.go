@@ -0,0 +1,120 @@
+package otelerr_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+	"github.com/JavierZunzunegui/xerrors/otelerr"
+)
+
+type event struct {
+	name       string
+	attributes []otelerr.Attribute
+}
+
+// spanRecorder is an in-memory otelerr.Span
+type spanRecorder struct {
+	events []event
+}
+
+func (s *spanRecorder) AddEvent(name string, attributes []otelerr.Attribute) {
+	s.events = append(s.events, event{name: name, attributes: attributes})
+}
+
+type fooError struct{}
+
+func (fooError) Error() string { return "foo" }
+
+func TestRecordError(t *testing.T) {
+	decoded, err := xerrors.DecodeJSON([]byte(`[
+		{"type":"xerrors.string","message":"bar"},
+		{"type":"xerrors.stack","frames":[
+			{"function":"github.com/foo/bar.Baz","file":"/src/bar/baz.go","line":12},
+			{"function":"main.main","file":"/src/main.go","line":5}
+		]},
+		{"type":"xerrors.string","message":"foo"},
+		{"type":"xerrors.stack","frames":[
+			{"function":"github.com/foo/foo.Foo","file":"/src/foo/foo.go","line":3}
+		]}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected decoding error: %s", err)
+	}
+
+	scenarios := []struct {
+		name           string
+		err            error
+		expectedEvents []event
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "unwrapped",
+			err:  fooError{},
+			expectedEvents: []event{{
+				name: "exception",
+				attributes: []otelerr.Attribute{
+					{Key: "exception.type", Value: "otelerr_test.fooError"},
+					{Key: "exception.message", Value: "foo"},
+				},
+			}},
+		},
+		{
+			name: "stacks",
+			err:  decoded,
+			expectedEvents: []event{{
+				name: "exception",
+				attributes: []otelerr.Attribute{
+					{Key: "exception.type", Value: "xerrors.string"},
+					{Key: "exception.message", Value: "bar: foo"},
+					{Key: "exception.stacktrace", Value: "github.com/foo/bar.Baz\n\t/src/bar/baz.go:12\n" +
+						"main.main\n\t/src/main.go:5\n" +
+						"\n" +
+						"github.com/foo/foo.Foo\n\t/src/foo/foo.go:3"},
+				},
+			}},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			span := &spanRecorder{}
+			otelerr.RecordError(span, s.err)
+
+			if !reflect.DeepEqual(span.events, s.expectedEvents) {
+				t.Errorf("expected events %v, got %v", s.expectedEvents, span.events)
+			}
+		})
+	}
+}
+
+func TestAttributes_wrapped(t *testing.T) {
+	attributes := otelerr.Attributes(xerrors.Wrap(fooError{}, xerrors.New("bar")))
+
+	if len(attributes) != 3 {
+		t.Fatalf("expected 3 attributes, got %v", attributes)
+	}
+
+	if out, expectedOut := attributes[0].Value, "otelerr_test.fooError"; out != expectedOut {
+		t.Errorf("expected type %q, got %q", expectedOut, out)
+	}
+
+	if out, expectedOut := attributes[1].Value, "bar: foo"; out != expectedOut {
+		t.Errorf("expected message %q, got %q", expectedOut, out)
+	}
+
+	if out := attributes[2].Value; !strings.HasPrefix(out, "github.com/JavierZunzunegui/xerrors/otelerr_test.TestAttributes_wrapped\n\t") {
+		t.Errorf("expected stacktrace to start at the test, got %q", out)
+	}
+}
+
+func TestStacktrace_trimmed(t *testing.T) {
+	err := xerrors.WrapWithOpts(fooError{}, nil, xerrors.StackOpts{Depth: 1, Trim: xerrors.TrimBasename})
+
+	if out, expectedOut := otelerr.Stacktrace(err), "github.com/JavierZunzunegui/xerrors/otelerr_test.TestStacktrace_trimmed\n\totelerr_test.go:"; !strings.HasPrefix(out, expectedOut) {
+		t.Errorf("expected stacktrace to start with %q, got %q", expectedOut, out)
+	}
+}
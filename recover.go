@@ -0,0 +1,131 @@
+package xerrors
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// PanicError is the payload recording a recovered panic.
+// It is produced by Recover and Go, do not initialise it directly.
+type PanicError struct {
+	value interface{}
+}
+
+// Value is a getter for the value the goroutine panicked with.
+func (err *PanicError) Value() interface{} {
+	return err.value
+}
+
+// ErrorToBuffer makes PanicError implement BufferError.
+// The format is "panic: {value}", where value is printed as by fmt.Print.
+func (err *PanicError) ErrorToBuffer(buf *bytes.Buffer) {
+	buf.WriteString("panic: ")
+
+	switch v := err.value.(type) {
+	case error:
+		buf.WriteString(v.Error())
+	case string:
+		buf.WriteString(v)
+	default:
+		fmt.Fprint(buf, v)
+	}
+}
+
+// Error is the string format of PanicError.ErrorToBuffer
+func (err *PanicError) Error() string {
+	return BufferErrorToString(err)
+}
+
+// Recover converts a panic into a WrappingError, and is intended to be deferred:
+//
+//	func work() (err error) {
+//	  defer xerrors.Recover(&err)
+//	  ...
+//	}
+//
+// If the goroutine is not panicking it does nothing.
+// Otherwise it stops the panic and sets *errp to a PanicError payload wrapping the previous value of *errp (if any),
+// with a StackError starting at the frame that panicked.
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	*errp = recovered(*errp, r)
+}
+
+// Go runs f in a new goroutine, recovering any panic as Recover does.
+// The returned channel receives f's error (nil included) once it has completed, and is then closed.
+func Go(f func() error) <-chan error {
+	c := make(chan error, 1)
+
+	go func() {
+		defer close(c)
+
+		c <- safeCall(f)
+	}()
+
+	return c
+}
+
+// safeCall calls f, recovering any panic
+func safeCall(f func() error) (err error) {
+	defer Recover(&err)
+
+	return f()
+}
+
+func recovered(err error, r interface{}) error {
+	pErr := &PanicError{value: r}
+
+	var wErr *WrappingError
+	if err == nil {
+		wErr = &WrappingError{payload: pErr}
+	} else {
+		wErr = merge(err, pErr)
+	}
+
+	return &WrappingError{
+		payload: newPanicStackError(defaultDepth),
+		next:    wErr,
+	}
+}
+
+// maxPanicFrames bounds the frames between the recovering function and the panicking one
+const maxPanicFrames = 32
+
+// newPanicStackError is as newStackError, but must be called from within a deferred function of a panicking
+// goroutine.
+// The stack starts at the frame that panicked, excluding the deferred call and the runtime's panic handling.
+func newPanicStackError(depth uint8) *StackError {
+	frames := make([]uintptr, maxPanicFrames+int(depth))
+	frames = frames[:runtime.Callers(1, frames)]
+
+	for i, pc := range frames {
+		if f := runtime.FuncForPC(pc - 1); f != nil && f.Name() == "runtime.gopanic" {
+			frames = frames[i+1:]
+
+			// runtime panics (nil dereference, index out of range, ...) are raised via further runtime functions
+			for len(frames) != 0 {
+				f = runtime.FuncForPC(frames[0] - 1)
+				if f == nil || !strings.HasPrefix(f.Name(), "runtime.") {
+					break
+				}
+				frames = frames[1:]
+			}
+
+			break
+		}
+	}
+
+	if len(frames) > int(depth) {
+		frames = frames[:depth]
+	}
+
+	return &StackError{
+		frames: frames,
+	}
+}
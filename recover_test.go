@@ -0,0 +1,109 @@
+package xerrors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func panickingFunc() {
+	panic("boom")
+}
+
+func nilDereferenceFunc() {
+	var p *ptrError
+	_ = p.s
+}
+
+func TestRecover(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		f                func() error
+		expectedOutput   string
+		expectedFunction string
+	}{
+		{
+			name:           "noPanic",
+			f:              func() error { return nil },
+			expectedOutput: "",
+		},
+		{
+			name:           "noPanicError",
+			f:              func() error { return xerrors.New("foo") },
+			expectedOutput: "foo",
+		},
+		{
+			name:             "stringPanic",
+			f:                func() error { panickingFunc(); return nil },
+			expectedOutput:   "panic: boom",
+			expectedFunction: "xerrors_test.panickingFunc",
+		},
+		{
+			name:             "errorPanic",
+			f:                func() error { panic(xerrors.New("foo")) },
+			expectedOutput:   "panic: foo",
+			expectedFunction: "xerrors_test.TestRecover.func",
+		},
+		{
+			name:             "runtimePanic",
+			f:                func() error { nilDereferenceFunc(); return nil },
+			expectedOutput:   "panic: runtime error: invalid memory address or nil pointer dereference",
+			expectedFunction: "xerrors_test.nilDereferenceFunc",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			err := <-xerrors.Go(scenario.f)
+
+			if scenario.expectedOutput == "" {
+				if err != nil {
+					t.Fatalf("expected nil error, got %q", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected non-nil error")
+			}
+
+			if out := err.Error(); out != scenario.expectedOutput {
+				t.Fatalf("expected %q got %q", scenario.expectedOutput, out)
+			}
+
+			if scenario.expectedFunction == "" {
+				return
+			}
+
+			if _, ok := xerrors.FindTyped(err, (*xerrors.PanicError)(nil)).(*xerrors.PanicError); !ok {
+				t.Fatal("expected to find a PanicError")
+			}
+
+			stackErr, ok := xerrors.Find(err, isStackError).(*xerrors.StackError)
+			if !ok {
+				t.Fatal("expected to find a StackError")
+			}
+
+			frame, _ := stackErr.Frames().Next()
+			if !strings.Contains(frame.Function, scenario.expectedFunction) {
+				t.Fatalf("expected stack to start at %q, got %q", scenario.expectedFunction, frame.Function)
+			}
+		})
+	}
+}
+
+func TestRecover_wrapsError(t *testing.T) {
+	f := func() (err error) {
+		defer xerrors.Recover(&err)
+
+		err = xerrors.New("foo")
+		panic("boom")
+	}
+
+	if out, expectedOut := f().Error(), "panic: boom: foo"; out != expectedOut {
+		t.Fatalf("expected %q got %q", expectedOut, out)
+	}
+}
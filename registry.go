@@ -0,0 +1,286 @@
+package xerrors
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stackTypeName identifies StackErrors in encoded chains
+const stackTypeName = "xerrors.stack"
+
+// codec maps an error type or sentinel to the name identifying it in encoded chains
+type codec struct {
+	name string
+	// encode returns the structured data of the error, if nil KeyValueError is used
+	encode func(error) [][2]string
+	// decode reconstructs the error, returning nil if it can't
+	decode func(msg string, fields [][2]string) error
+}
+
+var registry = struct {
+	sync.RWMutex
+	byType     map[reflect.Type]*codec
+	bySentinel map[error]*codec
+	byName     map[string]*codec
+}{
+	byType:     make(map[reflect.Type]*codec),
+	bySentinel: make(map[error]*codec),
+	byName:     make(map[string]*codec),
+}
+
+func registerType(t reflect.Type, c *codec) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.byName[c.name]; ok {
+		panic("xerrors: duplicate registration of name " + c.name)
+	}
+
+	if _, ok := registry.byType[t]; !ok {
+		registry.byType[t] = c
+	}
+	registry.byName[c.name] = c
+}
+
+func registerSentinel(sentinel error, name string) {
+	c := &codec{
+		name:   name,
+		decode: func(string, [][2]string) error { return sentinel },
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.byName[name]; ok {
+		panic("xerrors: duplicate registration of name " + name)
+	}
+
+	if _, ok := registry.bySentinel[sentinel]; !ok {
+		registry.bySentinel[sentinel] = c
+	}
+	registry.byName[name] = c
+}
+
+// Register declares the error type T under a stable name, identifying it in encoded chains (see EncodeJSON) and
+// allowing them to be decoded back into T.
+// It is intended to be called from init functions, and panics if name is already registered, T is an interface or
+// decode is nil.
+//
+// encode returns the structured data of the error, and decode reconstructs it from its message and that data.
+// If encode is nil the error's KeyValueError implementation, if any, is used instead.
+// If decode returns an error, or T was never registered in the decoding process, the error is decoded as a
+// RemoteError with the same name, message and structured data.
+//
+// The same type may be registered under several names, for example to keep decoding errors encoded by older
+// versions after a rename.
+// The first registered name is used for encoding, all are decoded.
+//
+// [PROPOSAL NOTES]
+//
+// Names are expected to be prefixed by the package declaring the type, i.e. "mypkg.MyError".
+// This package registers its own types and the context package errors, see TypeName.
+func Register[T error](name string, encode func(T) [][2]string, decode func(msg string, fields [][2]string) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Interface {
+		panic("xerrors: can't register interface type " + t.String())
+	}
+
+	if decode == nil {
+		panic("xerrors: can't register " + name + " with a nil decode")
+	}
+
+	c := &codec{
+		name: name,
+		decode: func(msg string, fields [][2]string) error {
+			out, err := decode(msg, fields)
+			if err != nil {
+				return nil
+			}
+			return out
+		},
+	}
+
+	if encode != nil {
+		c.encode = func(err error) [][2]string {
+			return encode(err.(T))
+		}
+	}
+
+	registerType(t, c)
+}
+
+// RegisterSentinel declares a sentinel error value under a stable name, see Register.
+// Decoding the name always produces the same sentinel, so comparisons by value keep working.
+// It panics if name is already registered.
+func RegisterSentinel(name string, sentinel error) {
+	registerSentinel(sentinel, name)
+}
+
+// TypeName returns the stable name identifying err's type, as declared via Register or RegisterSentinel.
+// For RemoteErrors it is the name they were encoded with, and for unregistered types the reflect type name.
+// Formatters exposing error types (such as JSON ones) are encouraged to use it.
+func TypeName(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return typeName(err)
+}
+
+// lookupError returns the codec for err, or nil if it is not registered
+func lookupError(err error) *codec {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	// the dynamic value, as comparable types such as structs with interface fields may hold unhashable values
+	if reflect.ValueOf(err).Comparable() {
+		if c, ok := registry.bySentinel[err]; ok {
+			return c
+		}
+	}
+
+	return registry.byType[reflect.TypeOf(err)]
+}
+
+func lookupName(name string) *codec {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.byName[name]
+}
+
+// typeName is the name identifying the type of err in encoded chains
+func typeName(err error) string {
+	if c := lookupError(err); c != nil {
+		return c.name
+	}
+
+	if rErr, ok := err.(*RemoteError); ok {
+		return rErr.typeName
+	}
+
+	return reflect.TypeOf(err).String()
+}
+
+func init() {
+	registerType(reflect.TypeOf((*stringError)(nil)), &codec{
+		name:   "xerrors.string",
+		decode: func(msg string, _ [][2]string) error { return New(msg) },
+	})
+
+	registerType(reflect.TypeOf((*formatError)(nil)), &codec{
+		name:   "xerrors.format",
+		encode: func(err error) [][2]string { return [][2]string{{"template", err.(*formatError).format}} },
+		decode: func(msg string, fields [][2]string) error {
+			if len(fields) != 1 || fields[0][0] != "template" {
+				return nil
+			}
+			return &formatError{format: fields[0][1], msg: msg}
+		},
+	})
+
+	registerType(reflect.TypeOf((*StackError)(nil)), &codec{
+		name: stackTypeName,
+		// StackErrors are encoded and decoded with their frames instead, see toWire and fromWire
+		encode: func(error) [][2]string { return nil },
+		decode: func(string, [][2]string) error { return nil },
+	})
+
+	registerType(reflect.TypeOf((*PanicError)(nil)), &codec{
+		name: "xerrors.panic",
+		decode: func(msg string, _ [][2]string) error {
+			if !strings.HasPrefix(msg, "panic: ") {
+				return nil
+			}
+			return &PanicError{value: msg[len("panic: "):]}
+		},
+	})
+
+	registerType(reflect.TypeOf((*ContextError)(nil)), &codec{
+		name:   "xerrors.context",
+		decode: decodeContextError,
+	})
+
+	registerType(reflect.TypeOf((*RetryError)(nil)), &codec{
+		name:   "xerrors.retry",
+		decode: decodeRetryError,
+	})
+
+	registerType(reflect.TypeOf((*AttemptError)(nil)), &codec{
+		name: "xerrors.attempt",
+		decode: func(_ string, fields [][2]string) error {
+			if len(fields) != 1 || fields[0][0] != "attempt" {
+				return nil
+			}
+			attempt, err := strconv.Atoi(fields[0][1])
+			if err != nil {
+				return nil
+			}
+			return &AttemptError{attempt: attempt}
+		},
+	})
+
+	registerType(reflect.TypeOf(Severity(0)), &codec{
+		name: "xerrors.severity",
+		decode: func(msg string, _ [][2]string) error {
+			for s, name := range severityNames {
+				if name == msg {
+					return Severity(s)
+				}
+			}
+			return nil
+		},
+	})
+
+	registerSentinel(context.Canceled, "context.Canceled")
+	registerSentinel(context.DeadlineExceeded, "context.DeadlineExceeded")
+}
+
+func decodeContextError(_ string, fields [][2]string) error {
+	cErr := &ContextError{}
+
+	for _, kv := range fields {
+		var err error
+
+		switch kv[0] {
+		case "deadline":
+			cErr.deadline, err = time.Parse(time.RFC3339Nano, kv[1])
+			cErr.hasDeadline = true
+		case "elapsed":
+			cErr.elapsed, err = time.ParseDuration(kv[1])
+		case "cause":
+			cErr.cause = New(kv[1])
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+
+	return cErr
+}
+
+func decodeRetryError(_ string, fields [][2]string) error {
+	rErr := &RetryError{}
+
+	for _, kv := range fields {
+		var err error
+
+		switch kv[0] {
+		case "retryable":
+			rErr.retryable, err = strconv.ParseBool(kv[1])
+		case "after":
+			rErr.after, err = time.ParseDuration(kv[1])
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+
+	return rErr
+}
@@ -0,0 +1,213 @@
+package xerrors_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+type registeredError struct {
+	code int
+}
+
+func (err *registeredError) Error() string { return "registered-" + strconv.Itoa(err.code) }
+
+var errRegisteredSentinel = xerrors.New("registered sentinel")
+
+func init() {
+	xerrors.Register(
+		"xerrors_test.registered",
+		func(err *registeredError) [][2]string {
+			return [][2]string{{"code", strconv.Itoa(err.code)}}
+		},
+		func(_ string, fields [][2]string) (*registeredError, error) {
+			if len(fields) != 1 || fields[0][0] != "code" {
+				return nil, xerrors.New("missing code")
+			}
+
+			code, err := strconv.Atoi(fields[0][1])
+			if err != nil {
+				return nil, err
+			}
+
+			return &registeredError{code: code}, nil
+		},
+	)
+
+	// alias, as if previously registered under a different name
+	xerrors.Register(
+		"xerrors_test.registeredV0",
+		nil,
+		func(msg string, _ [][2]string) (*registeredError, error) {
+			code, err := strconv.Atoi(msg)
+			if err != nil {
+				return nil, err
+			}
+
+			return &registeredError{code: code}, nil
+		},
+	)
+
+	xerrors.RegisterSentinel("xerrors_test.registeredSentinel", errRegisteredSentinel)
+}
+
+type errs []error
+
+func (errs) Error() string { return "errs" }
+
+// validationError is comparable by its type, but not if it holds an unhashable cause
+type validationError struct {
+	field string
+	cause error
+}
+
+func (err validationError) Error() string { return err.field + ": " + err.cause.Error() }
+
+func TestTypeName(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		err         error
+		expectedOut string
+	}{
+		{
+			name:        "nil",
+			err:         nil,
+			expectedOut: "",
+		},
+		{
+			name:        "builtin",
+			err:         xerrors.New("foo"),
+			expectedOut: "xerrors.string",
+		},
+		{
+			name:        "registered",
+			err:         &registeredError{code: 1},
+			expectedOut: "xerrors_test.registered",
+		},
+		{
+			name:        "sentinel",
+			err:         errRegisteredSentinel,
+			expectedOut: "xerrors_test.registeredSentinel",
+		},
+		{
+			name:        "unregistered",
+			err:         &BarError{},
+			expectedOut: "*xerrors_test.BarError",
+		},
+		{
+			name:        "unhashable",
+			err:         validationError{field: "name", cause: errs{xerrors.New("foo")}},
+			expectedOut: "xerrors_test.validationError",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			if out := xerrors.TypeName(scenario.err); out != scenario.expectedOut {
+				t.Fatalf("expected %q got %q", scenario.expectedOut, out)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		err := xerrors.Wrap(&registeredError{code: 7}, errRegisteredSentinel)
+
+		wErr, decodeErr := xerrors.DecodeJSON(xerrors.EncodeJSON(err))
+		if decodeErr != nil {
+			t.Fatalf("unexpected decoding error: %s", decodeErr)
+		}
+
+		if !xerrors.Similar(err, wErr) {
+			t.Fatalf("expected %q to be similar to %q", wErr, err)
+		}
+
+		rErr, ok := xerrors.FindTyped(wErr, (*registeredError)(nil)).(*registeredError)
+		if !ok || rErr.code != 7 {
+			t.Fatalf("expected to find the registered error, got %v", rErr)
+		}
+
+		if xerrors.Find(wErr, func(e error) bool { return e == errRegisteredSentinel }) == nil {
+			t.Fatal("expected to find the registered sentinel")
+		}
+	})
+
+	decodingScenarios := []struct {
+		name           string
+		data           string
+		expectedRemote bool
+	}{
+		{
+			name: "alias",
+			data: `[{"type":"xerrors_test.registeredV0","message":"3"}]`,
+		},
+		{
+			name:           "failedDecoding",
+			data:           `[{"type":"xerrors_test.registered","message":"registered-3"}]`,
+			expectedRemote: true,
+		},
+		{
+			name:           "unknownName",
+			data:           `[{"type":"xerrors_test.future","message":"registered-3","fields":[["code","3"]]}]`,
+			expectedRemote: true,
+		},
+	}
+
+	for _, scenario := range decodingScenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			decoded, err := xerrors.DecodeJSON([]byte(scenario.data))
+			if err != nil {
+				t.Fatalf("unexpected decoding error: %s", err)
+			}
+
+			wErr := decoded.(*xerrors.WrappingError)
+
+			_, isRemote := wErr.Payload().(*xerrors.RemoteError)
+			if isRemote != scenario.expectedRemote {
+				t.Fatalf("expected decoding as RemoteError to be %t, got %T", scenario.expectedRemote, wErr.Payload())
+			}
+
+			if !isRemote {
+				if rErr := wErr.Payload().(*registeredError); rErr.code != 3 {
+					t.Fatalf("expected code 3, got %d", rErr.code)
+				}
+			}
+		})
+	}
+
+	t.Run("duplicateName", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+
+		xerrors.RegisterSentinel("xerrors_test.registered", xerrors.New("foo"))
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+
+		xerrors.Register[error]("xerrors_test.interface", nil, nil)
+	})
+
+	t.Run("nilDecode", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+
+		xerrors.Register[*registeredError]("xerrors_test.nilDecode", nil, nil)
+	})
+}
@@ -0,0 +1,259 @@
+package xerrors
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// RetryError is the payload marking whether an operation that failed may be retried.
+// Do not initialise a RetryError directly, use Retryable, Permanent or RetryAfter.
+//
+// [PROPOSAL NOTES]
+//
+// Only the outermost RetryError in a chain is relevant, the layers wrapping an error are expected to know better than
+// the ones below whether retrying makes sense.
+type RetryError struct {
+	retryable bool
+	after     time.Duration
+}
+
+var (
+	// Retryable marks an error as retryable.
+	Retryable error = &RetryError{retryable: true}
+
+	// Permanent marks an error as not retryable.
+	Permanent error = &RetryError{}
+)
+
+// RetryAfter marks an error as retryable, not before the duration provided.
+func RetryAfter(d time.Duration) error {
+	return &RetryError{retryable: true, after: d}
+}
+
+// Retryable is a getter for whether the error is retryable.
+func (err *RetryError) Retryable() bool {
+	return err.retryable
+}
+
+// After is a getter for the minimum time to wait before retrying, 0 if unspecified.
+func (err *RetryError) After() time.Duration {
+	return err.after
+}
+
+// ErrorToBuffer makes RetryError implement BufferError.
+// The format is "retryable", "permanent" or "retry after {after}".
+func (err *RetryError) ErrorToBuffer(buf *bytes.Buffer) {
+	switch {
+	case !err.retryable:
+		buf.WriteString("permanent")
+	case err.after == 0:
+		buf.WriteString("retryable")
+	default:
+		buf.WriteString("retry after ")
+		buf.WriteString(err.after.String())
+	}
+}
+
+// Error is the string format of RetryError.ErrorToBuffer
+func (err *RetryError) Error() string {
+	return BufferErrorToString(err)
+}
+
+// KeyValueErrorData makes RetryError implement KeyValueError.
+// The keys are "retryable" and, if specified, "after".
+func (err *RetryError) KeyValueErrorData() [][2]string {
+	out := [][2]string{{"retryable", strconv.FormatBool(err.retryable)}}
+
+	if err.after != 0 {
+		out = append(out, [2]string{"after", err.after.String()})
+	}
+
+	return out
+}
+
+func isRetryError(err error) bool {
+	_, ok := err.(*RetryError)
+	return ok
+}
+
+// IsRetryable reports whether the outermost RetryError in the wrapping chain marks it as retryable.
+// It is false if there are no RetryErrors.
+func IsRetryable(err error) bool {
+	rErr, ok := Find(err, isRetryError).(*RetryError)
+	return ok && rErr.retryable
+}
+
+// IsPermanent reports whether the outermost RetryError in the wrapping chain marks it as not retryable.
+// It is false if there are no RetryErrors.
+func IsPermanent(err error) bool {
+	rErr, ok := Find(err, isRetryError).(*RetryError)
+	return ok && !rErr.retryable
+}
+
+// RetryDelay returns the minimum time to wait before retrying, as set by the outermost RetryError via RetryAfter.
+// It returns false if the outermost RetryError specifies no such time, or if there are no RetryErrors.
+func RetryDelay(err error) (time.Duration, bool) {
+	rErr, ok := Find(err, isRetryError).(*RetryError)
+	if !ok || !rErr.retryable || rErr.after == 0 {
+		return 0, false
+	}
+
+	return rErr.after, true
+}
+
+// AttemptError is the payload recording which attempt of Retry an error belongs to.
+// Do not initialise an AttemptError directly, use Retry.
+type AttemptError struct {
+	attempt int
+}
+
+// Attempt is a getter for the attempt number, starting at 1.
+func (err *AttemptError) Attempt() int {
+	return err.attempt
+}
+
+// Error's format is "attempt {attempt}".
+func (err *AttemptError) Error() string {
+	return "attempt " + strconv.Itoa(err.attempt)
+}
+
+// KeyValueErrorData makes AttemptError implement KeyValueError, with the key "attempt".
+func (err *AttemptError) KeyValueErrorData() [][2]string {
+	return [][2]string{{"attempt", strconv.Itoa(err.attempt)}}
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	Multiplier:     2,
+}
+
+// RetryPolicy defines how Retry retries.
+// Fields left to their zero value take defaults, except MaxBackoff and Jitter.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls, 3 by default or if not positive.
+	MaxAttempts int
+	// InitialBackoff is the delay after the first attempt, 100ms by default.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, unless RetryAfter requires a longer delay.
+	// There is no cap if it is 0.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the delay increases with every attempt, 2 by default.
+	Multiplier float64
+	// Jitter randomizes delays by up to the given fraction in either direction, i.e. 0.1 is ±10%.
+	Jitter float64
+	// Sleep waits for the delay or until ctx is done, returning ctx.Err() in the latter case.
+	// It uses a timer by default, and may be replaced for testing.
+	Sleep func(ctx context.Context, d time.Duration) error
+	// Rand returns numbers in [0, 1) for Jitter, math/rand.Float64 by default.
+	Rand func() float64
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryPolicy.MaxAttempts
+	}
+
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = defaultRetryPolicy.InitialBackoff
+	}
+
+	if p.Multiplier == 0 {
+		p.Multiplier = defaultRetryPolicy.Multiplier
+	}
+
+	if p.Sleep == nil {
+		p.Sleep = sleep
+	}
+
+	if p.Rand == nil {
+		p.Rand = rand.Float64
+	}
+
+	return p
+}
+
+// backoff is the delay after the given attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+		if (p.MaxBackoff != 0 && d >= float64(p.MaxBackoff)) || d >= math.MaxInt64 {
+			break
+		}
+	}
+
+	if p.Jitter != 0 {
+		d *= 1 + p.Jitter*(2*p.Rand()-1)
+	}
+
+	// capping after the jitter, so it never exceeds MaxBackoff
+	if p.MaxBackoff != 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	// out of range conversions are undefined, and may produce negative durations
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(d)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Retry calls f until it succeeds, with exponential backoff between attempts as defined by policy.
+// It stops early if an error IsPermanent, and waits at least the RetryDelay of errors if they have one.
+//
+// If all attempts fail, the errors of every attempt are returned in a single chain, latest attempt first.
+// Each is preceded by an AttemptError, i.e. "attempt 3: {err3}: attempt 2: {err2}: attempt 1: {err1}".
+// If ctx is done while waiting the output of ContextErr wraps the chain.
+// Retry adds no stacks of its own other than that of ContextErr.
+func Retry(ctx context.Context, policy RetryPolicy, f func() error) error {
+	policy = policy.withDefaults()
+
+	start := time.Now()
+
+	var out *WrappingError
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		attemptErr := merge(err, &AttemptError{attempt: attempt})
+		if out == nil {
+			out = attemptErr
+		} else {
+			out = merge(out, attemptErr)
+		}
+
+		if attempt >= policy.MaxAttempts || IsPermanent(err) {
+			return out
+		}
+
+		delay := policy.backoff(attempt)
+		if minDelay, ok := RetryDelay(err); ok && minDelay > delay {
+			delay = minDelay
+		}
+
+		if policy.Sleep(ctx, delay) != nil {
+			return merge(out, ContextErr(ctx, start))
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package xerrors_test
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestRetryMarkers(t *testing.T) {
+	scenarios := []struct {
+		name              string
+		err               error
+		expectedRetryable bool
+		expectedPermanent bool
+		expectedDelay     time.Duration
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "unmarked",
+			err:  xerrors.Wrap(xerrors.New("foo"), xerrors.New("bar")),
+		},
+		{
+			name:              "retryable",
+			err:               xerrors.Wrap(xerrors.New("foo"), xerrors.Retryable),
+			expectedRetryable: true,
+		},
+		{
+			name:              "permanent",
+			err:               xerrors.Wrap(xerrors.New("foo"), xerrors.Permanent),
+			expectedPermanent: true,
+		},
+		{
+			name:              "retryAfter",
+			err:               xerrors.Wrap(xerrors.New("foo"), xerrors.RetryAfter(time.Second)),
+			expectedRetryable: true,
+			expectedDelay:     time.Second,
+		},
+		{
+			name:              "outermostWins",
+			err:               xerrors.Wrap(xerrors.Wrap(xerrors.New("foo"), xerrors.Retryable), xerrors.Permanent),
+			expectedPermanent: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if retryable := xerrors.IsRetryable(s.err); retryable != s.expectedRetryable {
+				t.Errorf("expected IsRetryable %t, got %t", s.expectedRetryable, retryable)
+			}
+
+			if permanent := xerrors.IsPermanent(s.err); permanent != s.expectedPermanent {
+				t.Errorf("expected IsPermanent %t, got %t", s.expectedPermanent, permanent)
+			}
+
+			if delay, ok := xerrors.RetryDelay(s.err); delay != s.expectedDelay || ok != (s.expectedDelay != 0) {
+				t.Errorf("expected RetryDelay %s, got %s (%t)", s.expectedDelay, delay, ok)
+			}
+		})
+	}
+}
+
+func TestRetryError_Error(t *testing.T) {
+	for _, s := range []struct {
+		err      error
+		expected string
+	}{
+		{xerrors.Retryable, "retryable"},
+		{xerrors.Permanent, "permanent"},
+		{xerrors.RetryAfter(1500 * time.Millisecond), "retry after 1.5s"},
+	} {
+		if out := s.err.Error(); out != s.expected {
+			t.Errorf("expected %q, got %q", s.expected, out)
+		}
+	}
+}
+
+type sleepRecorder struct {
+	delays []time.Duration
+	// cancels the context on the given call, starting at 1
+	cancelOn int
+	cancel   context.CancelFunc
+}
+
+func (r *sleepRecorder) Sleep(ctx context.Context, d time.Duration) error {
+	r.delays = append(r.delays, d)
+	if len(r.delays) == r.cancelOn {
+		r.cancel()
+	}
+	return ctx.Err()
+}
+
+func TestRetry(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		policy         xerrors.RetryPolicy
+		errs           []error
+		cancelOn       int
+		expectedOutput string
+		// the output starts with the non-deterministic "context done after {elapsed}: "
+		expectCanceled bool
+		expectedDelays []time.Duration
+		expectedCalls  int
+	}{
+		{
+			name:          "success",
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:           "successAfterRetries",
+			errs:           []error{xerrors.New("foo"), xerrors.New("bar"), nil},
+			expectedDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			expectedCalls:  3,
+		},
+		{
+			name:           "exhausted",
+			errs:           []error{xerrors.New("foo"), xerrors.New("bar"), xerrors.New("baz"), nil},
+			expectedOutput: "attempt 3: baz: attempt 2: bar: attempt 1: foo",
+			expectedDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			expectedCalls:  3,
+		},
+		{
+			name:   "maxBackoff",
+			policy: xerrors.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second, Multiplier: 3, MaxBackoff: 5 * time.Second},
+			errs: []error{
+				xerrors.New("foo"), xerrors.New("foo"), xerrors.New("foo"), xerrors.New("foo"),
+			},
+			expectedOutput: "attempt 4: foo: attempt 3: foo: attempt 2: foo: attempt 1: foo",
+			expectedDelays: []time.Duration{time.Second, 3 * time.Second, 5 * time.Second},
+			expectedCalls:  4,
+		},
+		{
+			name:   "jitter",
+			policy: xerrors.RetryPolicy{MaxAttempts: 2, Jitter: 0.5, Rand: func() float64 { return 0.75 }},
+			errs:   []error{xerrors.New("foo"), xerrors.New("bar")},
+			// 100ms * (1 + 0.5*(2*0.75-1))
+			expectedOutput: "attempt 2: bar: attempt 1: foo",
+			expectedDelays: []time.Duration{125 * time.Millisecond},
+			expectedCalls:  2,
+		},
+		{
+			name:   "jitterCapped",
+			policy: xerrors.RetryPolicy{MaxAttempts: 2, MaxBackoff: 110 * time.Millisecond, Jitter: 0.5, Rand: func() float64 { return 0.75 }},
+			errs:   []error{xerrors.New("foo"), xerrors.New("bar")},
+			// 125ms after the jitter, capped
+			expectedOutput: "attempt 2: bar: attempt 1: foo",
+			expectedDelays: []time.Duration{110 * time.Millisecond},
+			expectedCalls:  2,
+		},
+		{
+			name:           "negativeMaxAttempts",
+			policy:         xerrors.RetryPolicy{MaxAttempts: -1},
+			errs:           []error{xerrors.New("foo"), xerrors.New("bar"), xerrors.New("baz"), nil},
+			expectedOutput: "attempt 3: baz: attempt 2: bar: attempt 1: foo",
+			expectedDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			expectedCalls:  3,
+		},
+		{
+			name:           "permanent",
+			errs:           []error{xerrors.New("foo"), xerrors.Wrap(xerrors.New("bar"), xerrors.Permanent), nil},
+			expectedOutput: "attempt 2: permanent: bar: attempt 1: foo",
+			expectedDelays: []time.Duration{100 * time.Millisecond},
+			expectedCalls:  2,
+		},
+		{
+			name:           "retryAfter",
+			errs:           []error{xerrors.Wrap(xerrors.New("foo"), xerrors.RetryAfter(time.Second)), nil},
+			expectedDelays: []time.Duration{time.Second},
+			expectedCalls:  2,
+		},
+		{
+			name:           "canceled",
+			errs:           []error{xerrors.New("foo"), xerrors.New("bar"), nil},
+			cancelOn:       2,
+			expectedOutput: "context canceled: attempt 2: bar: attempt 1: foo",
+			expectCanceled: true,
+			expectedDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+			expectedCalls:  2,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			recorder := &sleepRecorder{cancelOn: s.cancelOn, cancel: cancel}
+			policy := s.policy
+			policy.Sleep = recorder.Sleep
+
+			calls := 0
+			err := xerrors.Retry(ctx, policy, func() error {
+				calls++
+				return s.errs[calls-1]
+			})
+
+			var output string
+			if err != nil {
+				output = err.Error()
+			}
+
+			if s.expectCanceled {
+				if !xerrors.IsCanceled(err) {
+					t.Errorf("expected IsCanceled, got %q", output)
+				}
+				if !strings.HasPrefix(output, "context done after ") || !strings.HasSuffix(output, ": "+s.expectedOutput) {
+					t.Errorf("expected output ending in %q, got %q", s.expectedOutput, output)
+				}
+			} else if output != s.expectedOutput {
+				t.Errorf("expected output %q, got %q", s.expectedOutput, output)
+			}
+
+			if calls != s.expectedCalls {
+				t.Errorf("expected %d calls, got %d", s.expectedCalls, calls)
+			}
+
+			if !reflect.DeepEqual(recorder.delays, s.expectedDelays) {
+				t.Errorf("expected delays %v, got %v", s.expectedDelays, recorder.delays)
+			}
+		})
+	}
+}
+
+func TestRetry_unboundedBackoff(t *testing.T) {
+	for _, multiplier := range []float64{0, 10} {
+		recorder := &sleepRecorder{}
+
+		xerrors.Retry(context.Background(), xerrors.RetryPolicy{MaxAttempts: 45, Multiplier: multiplier, Sleep: recorder.Sleep}, func() error {
+			return xerrors.New("foo")
+		})
+
+		for i, d := range recorder.delays {
+			if i != 0 && d < recorder.delays[i-1] {
+				t.Fatalf("multiplier %v: expected non-decreasing delays, got %v", multiplier, recorder.delays)
+			}
+		}
+
+		if last := recorder.delays[len(recorder.delays)-1]; last != math.MaxInt64 {
+			t.Errorf("multiplier %v: expected the last delay to be capped to %v, got %v", multiplier, time.Duration(math.MaxInt64), last)
+		}
+	}
+}
+
+func noSleep(context.Context, time.Duration) error { return nil }
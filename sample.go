@@ -0,0 +1,241 @@
+package xerrors
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SamplerOpts defines the behaviour of a Sampler.
+type SamplerOpts struct {
+	// Printer prints the first occurrence of every error, normally with its stacks.
+	// If nil, errors are printed via Error().
+	Printer *Printer
+	// Window is the minimum time between summaries of the same error, 1 minute if 0.
+	Window time.Duration
+	// Now is the clock of the Sampler, time.Now if nil.
+	Now func() time.Time
+	// MaxEntries bounds the number of errors tracked at once, 10,000 if 0.
+	// When exceeded, the error seen the longest ago is forgotten, with a summary logged for any pending occurrences.
+	MaxEntries int
+}
+
+// Sampler logs errors, rate limiting repeated occurrences of the same error.
+// Errors are identified by their Fingerprint.
+// The first occurrence is logged in full, later ones are counted and logged as summaries, at most once per window:
+// "{error} (fingerprint {fingerprint}): seen {count} times in last {elapsed}"
+// Summaries are logged by the first occurrence after the window has elapsed, by Flush, or periodically by Run.
+// It is safe to be used concurrently.
+type Sampler struct {
+	log        func(string)
+	printer    *Printer
+	window     time.Duration
+	now        func() time.Time
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*sampleEntry
+}
+
+type sampleEntry struct {
+	// latest occurrence, printed in summaries
+	err         error
+	windowStart time.Time
+	// occurrences since windowStart
+	count int
+	// time of the latest occurrence
+	lastSeen time.Time
+}
+
+// NewSampler initialises a Sampler, logging via log.
+func NewSampler(log func(string), opts SamplerOpts) *Sampler {
+	s := &Sampler{
+		log:        log,
+		printer:    opts.Printer,
+		window:     opts.Window,
+		now:        opts.Now,
+		maxEntries: opts.MaxEntries,
+		entries:    make(map[string]*sampleEntry),
+	}
+
+	if s.printer == nil {
+		s.printer = defaultPrinter
+	}
+
+	if s.window == 0 {
+		s.window = time.Minute
+	}
+
+	if s.now == nil {
+		s.now = time.Now
+	}
+
+	if s.maxEntries <= 0 {
+		s.maxEntries = 10000
+	}
+
+	return s
+}
+
+// Log logs err in full if it is its first occurrence, or a summary if the window for it has elapsed.
+// Otherwise it only counts the occurrence.
+// Nil errors are ignored.
+func (s *Sampler) Log(err error) {
+	if err == nil {
+		return
+	}
+
+	fp := Fingerprint(err)
+	now := s.now()
+
+	s.mu.Lock()
+
+	e, ok := s.entries[fp]
+	if !ok {
+		var evicted string
+		if len(s.entries) >= s.maxEntries {
+			evicted = s.evict(now)
+		}
+
+		s.entries[fp] = &sampleEntry{err: err, windowStart: now, lastSeen: now}
+		s.mu.Unlock()
+
+		if evicted != "" {
+			s.log(evicted)
+		}
+		s.log(s.printer.String(err))
+		return
+	}
+
+	e.err = err
+	e.count++
+	e.lastSeen = now
+
+	if now.Sub(e.windowStart) < s.window {
+		s.mu.Unlock()
+		return
+	}
+
+	summary := e.summary(fp, now)
+	e.windowStart, e.count = now, 0
+
+	s.mu.Unlock()
+
+	s.log(summary)
+}
+
+// Flush logs the summaries of all errors with occurrences not yet logged, regardless of their windows, sorted.
+// Errors with no such occurrences and whose window has elapsed are forgotten, their next occurrence is logged in full.
+func (s *Sampler) Flush() {
+	s.flush(true)
+}
+
+// Run calls Flush every interval until ctx is done, and once more before returning.
+// Unlike Flush, the periodic calls only log the summaries of errors whose window has elapsed, so the summaries of an
+// ongoing storm of errors are logged at most once per window even if no further occurrences are seen.
+// If interval is not positive the window is used.
+func (s *Sampler) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = s.window
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flush(true)
+			return
+		case <-ticker.C:
+			s.flush(false)
+		}
+	}
+}
+
+// flush logs the pending summaries, of all errors if force or else only of those whose window has elapsed, and
+// forgets errors with no pending occurrences whose window has elapsed
+func (s *Sampler) flush(force bool) {
+	now := s.now()
+
+	var summaries []string
+
+	s.mu.Lock()
+
+	for fp, e := range s.entries {
+		elapsed := now.Sub(e.windowStart) >= s.window
+
+		if e.count == 0 {
+			if elapsed {
+				delete(s.entries, fp)
+			}
+			continue
+		}
+
+		if !force && !elapsed {
+			continue
+		}
+
+		summaries = append(summaries, e.summary(fp, now))
+		e.windowStart, e.count = now, 0
+	}
+
+	s.mu.Unlock()
+
+	sort.Strings(summaries)
+
+	for _, summary := range summaries {
+		s.log(summary)
+	}
+}
+
+// evict forgets the error seen the longest ago, returning its summary if it has pending occurrences.
+// It must be called with the lock held.
+func (s *Sampler) evict(now time.Time) string {
+	var (
+		oldestFP string
+		oldest   *sampleEntry
+	)
+
+	for fp, e := range s.entries {
+		if oldest == nil || e.lastSeen.Before(oldest.lastSeen) {
+			oldestFP, oldest = fp, e
+		}
+	}
+
+	delete(s.entries, oldestFP)
+
+	if oldest.count == 0 {
+		return ""
+	}
+
+	return oldest.summary(oldestFP, now)
+}
+
+func (e *sampleEntry) summary(fp string, now time.Time) string {
+	times := " times"
+	if e.count == 1 {
+		times = " time"
+	}
+
+	return e.err.Error() +
+		" (fingerprint " + fp + "): seen " + formatCount(e.count) + times +
+		" in last " + strconv.FormatInt(int64(now.Sub(e.windowStart)/time.Second), 10) + "s"
+}
+
+// formatCount formats n with comma thousands separators
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+
+	out := make([]byte, 0, len(s)+len(s)/3)
+	for i := range s {
+		if i != 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, s[i])
+	}
+
+	return string(out)
+}
@@ -0,0 +1,244 @@
+package xerrors_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type logRecorder struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (r *logRecorder) Log(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, s)
+}
+
+func (r *logRecorder) Pop() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.logs
+	r.logs = nil
+	return out
+}
+
+func TestSampler(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := &logRecorder{}
+
+	s := xerrors.NewSampler(recorder.Log, xerrors.SamplerOpts{
+		Printer: reverseColonPrinter,
+		Window:  time.Minute,
+		Now:     clock.Now,
+	})
+
+	fooErr := xerrors.WrapWithOpts(nil, xerrors.Errorf("foo %d", 1), xerrors.StackOpts{})
+	fooFP := xerrors.Fingerprint(fooErr)
+	barErr := xerrors.Wrap(xerrors.New("bar"), xerrors.New("wrapper"))
+	barFP := xerrors.Fingerprint(barErr)
+
+	steps := []struct {
+		name         string
+		do           func()
+		expectedLogs []string
+	}{
+		{
+			name:         "firstOccurrences",
+			do:           func() { s.Log(fooErr); s.Log(barErr); s.Log(nil) },
+			expectedLogs: []string{"foo 1", "bar: wrapper"},
+		},
+		{
+			name: "repeatsWithinWindow",
+			do: func() {
+				var wg sync.WaitGroup
+				for i := 0; i < 1233; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						s.Log(xerrors.WrapWithOpts(nil, xerrors.Errorf("foo %d", i), xerrors.StackOpts{}))
+					}(i)
+				}
+				wg.Wait()
+
+				clock.Advance(30 * time.Second)
+				s.Log(barErr)
+			},
+			expectedLogs: nil,
+		},
+		{
+			name: "windowElapsed",
+			do: func() {
+				clock.Advance(30 * time.Second)
+				s.Log(fooErr)
+			},
+			expectedLogs: []string{"foo 1 (fingerprint " + fooFP + "): seen 1,234 times in last 60s"},
+		},
+		{
+			name: "flush",
+			do: func() {
+				clock.Advance(10 * time.Second)
+				s.Flush()
+			},
+			expectedLogs: []string{"wrapper: bar (fingerprint " + barFP + "): seen 1 time in last 70s"},
+		},
+		{
+			name: "flushForgets",
+			do: func() {
+				clock.Advance(2 * time.Minute)
+				s.Flush()
+				s.Log(barErr)
+			},
+			expectedLogs: []string{"bar: wrapper"},
+		},
+	}
+
+	for _, step := range steps {
+		step.do()
+
+		if logs := recorder.Pop(); !reflect.DeepEqual(logs, step.expectedLogs) {
+			t.Fatalf("%s: expected logs %q, got %q", step.name, step.expectedLogs, logs)
+		}
+	}
+}
+
+func TestSampler_maxEntries(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := &logRecorder{}
+
+	s := xerrors.NewSampler(recorder.Log, xerrors.SamplerOpts{
+		Window:     time.Minute,
+		Now:        clock.Now,
+		MaxEntries: 2,
+	})
+
+	newErr := func(msg string) error { return xerrors.WrapWithOpts(nil, xerrors.New(msg), xerrors.StackOpts{}) }
+	aFP, cFP := xerrors.Fingerprint(newErr("a")), xerrors.Fingerprint(newErr("c"))
+
+	steps := []struct {
+		name         string
+		do           func()
+		expectedLogs []string
+	}{
+		{
+			name:         "fill",
+			do:           func() { s.Log(newErr("a")); s.Log(newErr("b")) },
+			expectedLogs: []string{"a", "b"},
+		},
+		{
+			name: "evictIdle",
+			do: func() {
+				clock.Advance(time.Second)
+				s.Log(newErr("a"))
+				s.Log(newErr("c"))
+			},
+			expectedLogs: []string{"c"},
+		},
+		{
+			name: "evictPending",
+			do: func() {
+				clock.Advance(time.Second)
+				s.Log(newErr("c"))
+				clock.Advance(time.Second)
+				s.Log(newErr("d"))
+			},
+			expectedLogs: []string{"a (fingerprint " + aFP + "): seen 1 time in last 3s", "d"},
+		},
+		{
+			name:         "evictedIsNew",
+			do:           func() { s.Log(newErr("b")) },
+			expectedLogs: []string{"c (fingerprint " + cFP + "): seen 1 time in last 2s", "b"},
+		},
+	}
+
+	for _, step := range steps {
+		step.do()
+
+		if logs := recorder.Pop(); !reflect.DeepEqual(logs, step.expectedLogs) {
+			t.Fatalf("%s: expected logs %q, got %q", step.name, step.expectedLogs, logs)
+		}
+	}
+}
+
+func TestSampler_Run(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := &logRecorder{}
+
+	s := xerrors.NewSampler(recorder.Log, xerrors.SamplerOpts{
+		Window: time.Minute,
+		Now:    clock.Now,
+	})
+
+	fooErr := xerrors.WrapWithOpts(nil, xerrors.New("foo"), xerrors.StackOpts{})
+	fooFP := xerrors.Fingerprint(fooErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx, time.Millisecond)
+	}()
+
+	s.Log(fooErr)
+	s.Log(fooErr)
+	s.Log(fooErr)
+
+	// within the window, the periodic flushes log nothing
+	time.Sleep(20 * time.Millisecond)
+	if logs := recorder.Pop(); !reflect.DeepEqual(logs, []string{"foo"}) {
+		t.Fatalf("expected only the first occurrence logged, got %q", logs)
+	}
+
+	// once the window elapses the summary is logged without further occurrences
+	clock.Advance(time.Minute)
+
+	expectedLogs := []string{"foo (fingerprint " + fooFP + "): seen 2 times in last 60s"}
+	if logs := waitForLogs(recorder); !reflect.DeepEqual(logs, expectedLogs) {
+		t.Fatalf("expected logs %q, got %q", expectedLogs, logs)
+	}
+
+	// stopping flushes the pending occurrences, regardless of the window
+	clock.Advance(time.Second)
+	s.Log(fooErr)
+
+	cancel()
+	<-done
+
+	expectedLogs = []string{"foo (fingerprint " + fooFP + "): seen 1 time in last 1s"}
+	if logs := recorder.Pop(); !reflect.DeepEqual(logs, expectedLogs) {
+		t.Fatalf("expected logs %q, got %q", expectedLogs, logs)
+	}
+}
+
+// waitForLogs returns the logs of the recorder once there are any, or nil if none arrive in a second
+func waitForLogs(r *logRecorder) []string {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if logs := r.Pop(); logs != nil {
+			return logs
+		}
+	}
+	return nil
+}
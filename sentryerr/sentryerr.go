@@ -0,0 +1,246 @@
+// Package sentryerr exports wrapped errors as events in the Sentry event schema, and sends them to Sentry compatible
+// crash reporting backends.
+//
+// It follows the schema as documented in https://develop.sentry.dev/sdk/event-payloads/, without depending on any
+// Sentry SDK.
+package sentryerr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// Options defines how errors are converted to Events.
+type Options struct {
+	// InAppPrefixes are the module (package path) prefixes of frames belonging to the application.
+	// If empty, all frames except those of the standard library (see xerrors.IsStdlibFrame) are considered part of the
+	// application.
+	InAppPrefixes []string
+	// TagKeys are the keys of structured fields (see xerrors.KeyValueError) set as tags rather than as extra data.
+	TagKeys []string
+	// Now is the clock for event timestamps, time.Now if nil.
+	Now func() time.Time
+}
+
+// Event is a Sentry event.
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Exception   ExceptionList     `json:"exception"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+// ExceptionList holds the exceptions of an Event, oldest (causal) first.
+type ExceptionList struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception is a single payload of an error.
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace holds the frames of a StackError, oldest (outermost caller) first.
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a single stack frame.
+type Frame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+var severityLevels = map[xerrors.Severity]string{
+	xerrors.SeverityDebug:    "debug",
+	xerrors.SeverityInfo:     "info",
+	xerrors.SeverityWarn:     "warning",
+	xerrors.SeverityError:    "error",
+	xerrors.SeverityCritical: "fatal",
+}
+
+// NewEvent converts err into an Event, or returns nil for a nil error.
+//
+// Every non-StackError payload is an exception, with its xerrors.TypeName as type and its Error() as value.
+// StackErrors are the stacktrace of the payload they precede, which is the one that was wrapped along with them.
+// The level is taken from xerrors.SeverityOf, "error" by default, and the fingerprint is the xerrors.Fingerprint of
+// err so Sentry groups events as xerrors does.
+// Structured fields of payloads implementing xerrors.KeyValueError are set as extra data or tags, the outermost
+// taking precedence over repeated keys.
+func NewEvent(err error, opts Options) *Event {
+	if err == nil {
+		return nil
+	}
+
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+
+	event := &Event{
+		EventID:     newEventID(),
+		Timestamp:   now().UTC(),
+		Platform:    "go",
+		Level:       "error",
+		Fingerprint: []string{xerrors.Fingerprint(err)},
+	}
+
+	if severity, ok := xerrors.SeverityOf(err); ok {
+		event.Level = severityLevels[severity]
+	}
+
+	wErr, ok := err.(*xerrors.WrappingError)
+	if !ok {
+		event.Exception.Values = []Exception{{Type: xerrors.TypeName(err), Value: err.Error()}}
+		event.addFields(err, opts)
+		return event
+	}
+
+	var stack *xerrors.StackError
+
+	for ; wErr != nil; wErr = wErr.Next() {
+		if sErr, ok := wErr.Payload().(*xerrors.StackError); ok {
+			stack = sErr
+			continue
+		}
+
+		exception := Exception{
+			Type:  xerrors.TypeName(wErr.Payload()),
+			Value: wErr.Payload().Error(),
+		}
+
+		if stack != nil {
+			exception.Stacktrace = newStacktrace(stack, opts)
+			stack = nil
+		}
+
+		event.Exception.Values = append(event.Exception.Values, exception)
+		event.addFields(wErr.Payload(), opts)
+	}
+
+	// oldest first
+	values := event.Exception.Values
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+
+	return event
+}
+
+func (e *Event) addFields(err error, opts Options) {
+	kvErr, ok := err.(xerrors.KeyValueError)
+	if !ok {
+		return
+	}
+
+	for _, kv := range kvErr.KeyValueErrorData() {
+		m := &e.Extra
+		if isTagKey(kv[0], opts) {
+			m = &e.Tags
+		}
+
+		if *m == nil {
+			*m = make(map[string]string)
+		}
+
+		if _, ok := (*m)[kv[0]]; !ok {
+			(*m)[kv[0]] = kv[1]
+		}
+	}
+}
+
+func isTagKey(key string, opts Options) bool {
+	for _, tagKey := range opts.TagKeys {
+		if key == tagKey {
+			return true
+		}
+	}
+	return false
+}
+
+func newStacktrace(sErr *xerrors.StackError, opts Options) *Stacktrace {
+	frames := sErr.SymbolizedFrames()
+
+	out := &Stacktrace{Frames: make([]Frame, len(frames))}
+
+	for i, frame := range frames {
+		module, function := splitFunction(frame.Function)
+		file := xerrors.TrimPath(frame, sErr.PathTrim())
+
+		out.Frames[len(frames)-1-i] = Frame{
+			Function: function,
+			Module:   module,
+			Filename: filename(file),
+			AbsPath:  file,
+			Lineno:   frame.Line,
+			InApp:    isInApp(frame, module, opts),
+		}
+	}
+
+	return out
+}
+
+// splitFunction splits a function name, as in runtime.Frame.Function, into its package path and its name
+func splitFunction(function string) (string, string) {
+	slash := strings.LastIndex(function, "/")
+
+	dot := strings.Index(function[slash+1:], ".")
+	if dot == -1 {
+		return "", function
+	}
+
+	return function[:slash+1+dot], function[slash+1+dot+1:]
+}
+
+// filename is the last two elements of the path, i.e. the package directory and the file
+func filename(path string) string {
+	slash := strings.LastIndex(path, "/")
+	if slash == -1 {
+		return path
+	}
+
+	if prev := strings.LastIndex(path[:slash], "/"); prev != -1 {
+		return path[prev+1:]
+	}
+
+	return path
+}
+
+func isInApp(frame runtime.Frame, module string, opts Options) bool {
+	if module == "" {
+		return false
+	}
+
+	if len(opts.InAppPrefixes) == 0 {
+		return !xerrors.IsStdlibFrame(frame)
+	}
+
+	for _, prefix := range opts.InAppPrefixes {
+		if strings.HasPrefix(module, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,217 @@
+package sentryerr_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+	"github.com/JavierZunzunegui/xerrors/sentryerr"
+)
+
+var testNow = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// decodedErr is "foo: retry after 1s: bar" with a stack for "foo" and one for "bar"
+func decodedErr(t *testing.T) error {
+	t.Helper()
+
+	wErr, err := xerrors.DecodeJSON([]byte(`[
+		{"type":"xerrors.stack","frames":[
+			{"function":"github.com/foo/app.(*Server).handle","file":"/src/app/server.go","line":30},
+			{"function":"net/http.HandlerFunc.ServeHTTP","file":"/go/src/net/http/server.go","line":2166}
+		]},
+		{"type":"xerrors.string","message":"foo"},
+		{"type":"xerrors.retry","message":"retry after 1s","fields":[["retryable","true"],["after","1s"]]},
+		{"type":"xerrors.severity","message":"WARN"},
+		{"type":"xerrors.stack","frames":[
+			{"function":"github.com/foo/lib.Bar","file":"/mod/github.com/foo/lib@v1.0.0/bar.go","line":7},
+			{"function":"github.com/foo/app.(*Server).handle","file":"/src/app/server.go","line":28}
+		]},
+		{"type":"xerrors.string","message":"bar"}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected decoding error: %s", err)
+	}
+
+	return wErr
+}
+
+func TestNewEvent(t *testing.T) {
+	err := decodedErr(t)
+
+	event := sentryerr.NewEvent(err, sentryerr.Options{
+		InAppPrefixes: []string{"github.com/foo/app"},
+		TagKeys:       []string{"retryable"},
+		Now:           func() time.Time { return testNow },
+	})
+
+	if len(event.EventID) != 32 {
+		t.Errorf("expected a 32 character event ID, got %q", event.EventID)
+	}
+	event.EventID = ""
+
+	expectedEvent := &sentryerr.Event{
+		Timestamp: testNow,
+		Platform:  "go",
+		Level:     "warning",
+		Exception: sentryerr.ExceptionList{Values: []sentryerr.Exception{
+			{
+				Type:  "xerrors.string",
+				Value: "bar",
+				Stacktrace: &sentryerr.Stacktrace{Frames: []sentryerr.Frame{
+					{Function: "(*Server).handle", Module: "github.com/foo/app", Filename: "app/server.go", AbsPath: "/src/app/server.go", Lineno: 28, InApp: true},
+					{Function: "Bar", Module: "github.com/foo/lib", Filename: "lib@v1.0.0/bar.go", AbsPath: "/mod/github.com/foo/lib@v1.0.0/bar.go", Lineno: 7},
+				}},
+			},
+			{Type: "xerrors.severity", Value: "WARN"},
+			{Type: "xerrors.retry", Value: "retry after 1s"},
+			{
+				Type:  "xerrors.string",
+				Value: "foo",
+				Stacktrace: &sentryerr.Stacktrace{Frames: []sentryerr.Frame{
+					{Function: "HandlerFunc.ServeHTTP", Module: "net/http", Filename: "http/server.go", AbsPath: "/go/src/net/http/server.go", Lineno: 2166},
+					{Function: "(*Server).handle", Module: "github.com/foo/app", Filename: "app/server.go", AbsPath: "/src/app/server.go", Lineno: 30, InApp: true},
+				}},
+			},
+		}},
+		Fingerprint: []string{xerrors.Fingerprint(err)},
+		Tags:        map[string]string{"retryable": "true"},
+		Extra:       map[string]string{"after": "1s"},
+	}
+
+	if !reflect.DeepEqual(event, expectedEvent) {
+		got, _ := json.MarshalIndent(event, "", "  ")
+		want, _ := json.MarshalIndent(expectedEvent, "", "  ")
+		t.Errorf("expected event:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestNewEvent_defaults(t *testing.T) {
+	if event := sentryerr.NewEvent(nil, sentryerr.Options{}); event != nil {
+		t.Errorf("expected nil event, got %v", event)
+	}
+
+	event := sentryerr.NewEvent(decodedErr(t), sentryerr.Options{})
+
+	var inApp []bool
+	for _, frame := range event.Exception.Values[0].Stacktrace.Frames {
+		inApp = append(inApp, frame.InApp)
+	}
+
+	// only standard library frames are not in app
+	if expected := []bool{true, true}; !reflect.DeepEqual(inApp, expected) {
+		t.Errorf("expected in_app %v, got %v", expected, inApp)
+	}
+
+	if event := sentryerr.NewEvent(xerrors.New("foo"), sentryerr.Options{}); event.Level != "error" {
+		t.Errorf("expected level error, got %q", event.Level)
+	}
+}
+
+func TestNewEvent_trimmed(t *testing.T) {
+	err := xerrors.WrapWithOpts(xerrors.New("foo"), nil, xerrors.StackOpts{Depth: 1, Trim: xerrors.TrimBasename})
+
+	frames := sentryerr.NewEvent(err, sentryerr.Options{}).Exception.Values[0].Stacktrace.Frames
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame, got %v", frames)
+	}
+
+	if frame := frames[0]; frame.Filename != "sentryerr_test.go" || frame.AbsPath != "sentryerr_test.go" {
+		t.Errorf("expected trimmed file paths, got %q and %q", frame.Filename, frame.AbsPath)
+	}
+}
+
+func TestTransport_Send(t *testing.T) {
+	type received struct {
+		path, auth  string
+		header      map[string]interface{}
+		itemHeader  map[string]interface{}
+		event       sentryerr.Event
+		eventLength int
+	}
+
+	var (
+		got    received
+		status = http.StatusOK
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = received{path: r.URL.Path, auth: r.Header.Get("X-Sentry-Auth")}
+
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(nil, 1<<20)
+		for i := 0; scanner.Scan(); i++ {
+			switch i {
+			case 0:
+				_ = json.Unmarshal(scanner.Bytes(), &got.header)
+			case 1:
+				_ = json.Unmarshal(scanner.Bytes(), &got.itemHeader)
+			case 2:
+				got.eventLength = len(scanner.Bytes())
+				_ = json.Unmarshal(scanner.Bytes(), &got.event)
+			}
+		}
+
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/prefix/42"
+
+	transport, err := sentryerr.NewTransport(dsn)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	event := sentryerr.NewEvent(decodedErr(t), sentryerr.Options{Now: func() time.Time { return testNow }})
+
+	if err := transport.Send(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := "/prefix/api/42/envelope/"; got.path != expected {
+		t.Errorf("expected path %q, got %q", expected, got.path)
+	}
+
+	if !strings.Contains(got.auth, "sentry_key=public") {
+		t.Errorf("expected the public key in the auth header, got %q", got.auth)
+	}
+
+	if got.header["event_id"] != event.EventID || got.header["dsn"] != dsn {
+		t.Errorf("unexpected envelope header %v", got.header)
+	}
+
+	if got.itemHeader["type"] != "event" || got.itemHeader["length"] != float64(got.eventLength) {
+		t.Errorf("unexpected item header %v for an event of length %d", got.itemHeader, got.eventLength)
+	}
+
+	if !reflect.DeepEqual(&got.event, event) {
+		t.Errorf("expected event %v, got %v", event, got.event)
+	}
+
+	status = http.StatusTooManyRequests
+
+	if err := transport.Send(context.Background(), event); err == nil || err.Error() != "sentry envelope rejected with status 429" {
+		t.Errorf("expected rejection error, got %v", err)
+	}
+}
+
+func TestNewTransport_invalid(t *testing.T) {
+	for _, dsn := range []string{
+		"",
+		"https://sentry.example.com/42",
+		"https://public@sentry.example.com",
+		"https://public@sentry.example.com/",
+		"://public@sentry.example.com/42",
+	} {
+		if _, err := sentryerr.NewTransport(dsn); err == nil {
+			t.Errorf("expected error for DSN %q", dsn)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package sentryerr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+const sentryClient = "xerrors-sentryerr/1.0"
+
+var errInvalidDSN = xerrors.New("invalid DSN, expected {scheme}://{public_key}@{host}/{project_id}")
+
+// Transport sends Events to a Sentry compatible backend, as envelopes.
+type Transport struct {
+	// Client is the HTTP client used, http.DefaultClient if nil.
+	Client *http.Client
+
+	dsn      string
+	endpoint string
+	key      string
+}
+
+// NewTransport initialises a Transport sending Events to the project identified by dsn, of the form
+// "{scheme}://{public_key}@{host}[/{path}]/{project_id}".
+func NewTransport(dsn string) (*Transport, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, xerrors.Wrap(err, errInvalidDSN)
+	}
+
+	if u.Scheme == "" || u.Host == "" || u.User == nil || u.User.Username() == "" {
+		return nil, xerrors.Wrap(nil, errInvalidDSN)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+
+	slash := strings.LastIndex(path, "/")
+	if slash == -1 || slash == len(path)-1 {
+		return nil, xerrors.Wrap(nil, errInvalidDSN)
+	}
+
+	return &Transport{
+		dsn:      dsn,
+		endpoint: u.Scheme + "://" + u.Host + path[:slash] + "/api/" + path[slash+1:] + "/envelope/",
+		key:      u.User.Username(),
+	}, nil
+}
+
+// Send sends the Event, failing if the backend does not accept it.
+func (t *Transport) Send(ctx context.Context, event *Event) error {
+	body, err := t.envelope(event)
+	if err != nil {
+		return xerrors.Wrap(err, xerrors.New("encoding sentry envelope"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Wrap(err, xerrors.New("building sentry request"))
+	}
+
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client="+sentryClient+", sentry_key="+t.key)
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrors.Wrap(err, xerrors.New("sending sentry envelope"))
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return xerrors.Wrap(nil, xerrors.Errorf("sentry envelope rejected with status %d", resp.StatusCode))
+	}
+
+	return nil
+}
+
+// envelope encodes the Event as an envelope with a single item:
+//
+//	{"event_id":...,"sent_at":...,"dsn":...}
+//	{"type":"event","length":...}
+//	{event}
+func (t *Transport) envelope(event *Event) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(struct {
+		EventID string    `json:"event_id"`
+		SentAt  time.Time `json:"sent_at"`
+		DSN     string    `json:"dsn"`
+	}{event.EventID, time.Now().UTC(), t.dsn})
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.Write(header)
+	b.WriteString("\n")
+	b.WriteString(`{"type":"event","length":` + strconv.Itoa(len(payload)) + "}\n")
+	b.Write(payload)
+	b.WriteString("\n")
+
+	return b.Bytes(), nil
+}
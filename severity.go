@@ -0,0 +1,226 @@
+package xerrors
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strconv"
+)
+
+// Severity is the payload marking how severe an error is, and so the level it should be logged at.
+// It is best decided where the error originates, by wrapping it with one of the Severity constants.
+type Severity uint8
+
+const (
+	// SeverityDebug is for errors only relevant when debugging.
+	SeverityDebug Severity = iota
+	// SeverityInfo is for errors expected in normal operation.
+	SeverityInfo
+	// SeverityWarn is for errors that may need attention.
+	SeverityWarn
+	// SeverityError is for errors that need attention.
+	SeverityError
+	// SeverityCritical is for errors that need immediate attention.
+	SeverityCritical
+)
+
+var severityNames = [...]string{
+	SeverityDebug:    "DEBUG",
+	SeverityInfo:     "INFO",
+	SeverityWarn:     "WARN",
+	SeverityError:    "ERROR",
+	SeverityCritical: "CRITICAL",
+}
+
+// String is the name of the Severity, as the slog level names with the addition of "CRITICAL".
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return "Severity(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Error is the same as String, making Severity an error.
+func (s Severity) Error() string {
+	return s.String()
+}
+
+// Level makes Severity implement slog.Leveler.
+// SeverityCritical is 4 levels above slog.LevelError.
+func (s Severity) Level() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarn:
+		return slog.LevelWarn
+	case SeverityError:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
+
+// SeverityOf returns the most severe Severity in the wrapping chain, and false if there are none.
+func SeverityOf(err error) (Severity, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	wErr, ok := err.(*WrappingError)
+	if !ok {
+		wErr = &WrappingError{payload: err}
+	}
+
+	var (
+		out   Severity
+		found bool
+	)
+
+	for ; wErr != nil; wErr = wErr.next {
+		if s, ok := wErr.payload.(Severity); ok && (!found || s > out) {
+			out, found = s, true
+		}
+	}
+
+	return out, found
+}
+
+func isNotSeverity(err error) bool {
+	_, ok := err.(Severity)
+	return !ok
+}
+
+type severityFormatter struct {
+	f        Formatter
+	severity string
+	first    bool
+}
+
+func (f *severityFormatter) Init(wErr *WrappingError) {
+	f.f.Init(wErr)
+
+	f.severity = ""
+	if s, ok := SeverityOf(wErr); ok {
+		f.severity = s.String()
+	}
+
+	f.first = true
+}
+
+func (f *severityFormatter) Next() error {
+	err := f.f.Next()
+	for err != nil && !isNotSeverity(err) {
+		err = f.f.Next()
+	}
+	return err
+}
+
+func (f *severityFormatter) CustomFormat(err error, buf *bytes.Buffer) bool {
+	return f.f.CustomFormat(err, buf)
+}
+
+func (f *severityFormatter) Append(w *bytes.Buffer, msg []byte) {
+	if f.first {
+		f.first = false
+
+		if f.severity != "" {
+			w.WriteString("[")
+			w.WriteString(f.severity)
+			w.WriteString("] ")
+		}
+	}
+
+	f.f.Append(w, msg)
+}
+
+// NewSeverityFormatter provides a formatter factory that prefixes the output of the formatters of fFactory with the
+// SeverityOf the error, as "[WARN] ...", and omits the Severity payloads themselves.
+// Errors with no Severity are printed as by fFactory's formatters.
+func NewSeverityFormatter(fFactory func() Formatter) func() Formatter {
+	return func() Formatter {
+		return &severityFormatter{f: fFactory()}
+	}
+}
+
+type slogHandler struct {
+	h slog.Handler
+}
+
+// NewSlogHandler wraps h so that records holding errors amongst their attributes take the level of the errors'
+// SeverityOf, the most severe if there are multiple.
+// Records are otherwise passed unchanged to h, which decides if they are enabled based on their final level.
+// Errors in attributes added via WithAttrs are not considered.
+func NewSlogHandler(h slog.Handler) slog.Handler {
+	return &slogHandler{h: h}
+}
+
+// Enabled can't know the final level of the record, and so is true if h is enabled at any level a Severity may set.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.h.Enabled(ctx, level) || h.h.Enabled(ctx, SeverityCritical.Level())
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var (
+		severity Severity
+		found    bool
+	)
+
+	r.Attrs(func(a slog.Attr) bool {
+		if s, ok := attrSeverity(a); ok && (!found || s > severity) {
+			severity, found = s, true
+		}
+		return true
+	})
+
+	if found && severity.Level() != r.Level {
+		leveled := slog.NewRecord(r.Time, severity.Level(), r.Message, r.PC)
+		r.Attrs(func(a slog.Attr) bool {
+			leveled.AddAttrs(a)
+			return true
+		})
+		r = leveled
+	}
+
+	if !h.h.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	return h.h.Handle(ctx, r)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{h: h.h.WithAttrs(attrs)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{h: h.h.WithGroup(name)}
+}
+
+// attrSeverity is the most severe SeverityOf the errors in the attribute, including those within groups
+func attrSeverity(a slog.Attr) (Severity, bool) {
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return SeverityOf(err)
+		}
+	case slog.KindGroup:
+		var (
+			out   Severity
+			found bool
+		)
+
+		for _, ga := range v.Group() {
+			if s, ok := attrSeverity(ga); ok && (!found || s > out) {
+				out, found = s, true
+			}
+		}
+
+		return out, found
+	}
+
+	return 0, false
+}
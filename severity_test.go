@@ -0,0 +1,168 @@
+package xerrors_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestSeverityOf(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		err              error
+		expectedSeverity xerrors.Severity
+		expectedOK       bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "none",
+			err:  xerrors.Wrap(xerrors.New("foo"), xerrors.New("bar")),
+		},
+		{
+			name:             "unwrapped",
+			err:              xerrors.SeverityInfo,
+			expectedSeverity: xerrors.SeverityInfo,
+			expectedOK:       true,
+		},
+		{
+			name:             "debug",
+			err:              xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityDebug),
+			expectedSeverity: xerrors.SeverityDebug,
+			expectedOK:       true,
+		},
+		{
+			name:             "mostSevere",
+			err:              xerrors.Wrap(xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityCritical), xerrors.SeverityWarn),
+			expectedSeverity: xerrors.SeverityCritical,
+			expectedOK:       true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			severity, ok := xerrors.SeverityOf(s.err)
+			if severity != s.expectedSeverity || ok != s.expectedOK {
+				t.Errorf("expected %s (%t), got %s (%t)", s.expectedSeverity, s.expectedOK, severity, ok)
+			}
+		})
+	}
+}
+
+func TestNewSeverityFormatter(t *testing.T) {
+	p := xerrors.NewPrinter(xerrors.NewSeverityFormatter(xerrors.NewColonFormatter))
+
+	scenarios := []struct {
+		name           string
+		err            error
+		expectedOutput string
+	}{
+		{
+			name:           "none",
+			err:            xerrors.Wrap(xerrors.New("foo"), xerrors.New("bar")),
+			expectedOutput: "bar: foo",
+		},
+		{
+			name:           "outermost",
+			err:            xerrors.Wrap(xerrors.Wrap(xerrors.New("foo"), xerrors.New("bar")), xerrors.SeverityWarn),
+			expectedOutput: "[WARN] bar: foo",
+		},
+		{
+			name:           "mostSevere",
+			err:            xerrors.Wrap(xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityError), xerrors.SeverityInfo),
+			expectedOutput: "[ERROR] foo",
+		},
+		{
+			name:           "between",
+			err:            xerrors.Wrap(xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityDebug), xerrors.New("bar")),
+			expectedOutput: "[DEBUG] bar: foo",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := p.String(s.err); out != s.expectedOutput {
+				t.Errorf("expected %q, got %q", s.expectedOutput, out)
+			}
+		})
+	}
+}
+
+func TestNewSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(xerrors.NewSlogHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	scenarios := []struct {
+		name           string
+		log            func()
+		expectedOutput string
+	}{
+		{
+			name:           "noError",
+			log:            func() { logger.Info("msg", "foo", "bar") },
+			expectedOutput: "level=INFO msg=msg foo=bar\n",
+		},
+		{
+			name:           "noSeverity",
+			log:            func() { logger.Error("msg", "err", xerrors.New("foo")) },
+			expectedOutput: "level=ERROR msg=msg err=foo\n",
+		},
+		{
+			name:           "raised",
+			log:            func() { logger.Info("msg", "err", xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityCritical)) },
+			expectedOutput: "level=ERROR+4 msg=msg err=\"CRITICAL: foo\"\n",
+		},
+		{
+			name:           "enabledByError",
+			log:            func() { logger.Debug("msg", "err", xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityWarn)) },
+			expectedOutput: "level=WARN msg=msg err=\"WARN: foo\"\n",
+		},
+		{
+			name:           "loweredBelowEnabled",
+			log:            func() { logger.Error("msg", "err", xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityDebug)) },
+			expectedOutput: "",
+		},
+		{
+			name: "group",
+			log: func() {
+				logger.Info("msg", slog.Group("g", "err", xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityError)))
+			},
+			expectedOutput: "level=ERROR msg=msg g.err=\"ERROR: foo\"\n",
+		},
+		{
+			name: "withAttrs",
+			log: func() {
+				logger.With("foo", "bar").Debug("msg", "err", xerrors.Wrap(xerrors.New("foo"), xerrors.SeverityInfo))
+			},
+			expectedOutput: "level=INFO msg=msg foo=bar err=\"INFO: foo\"\n",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			buf.Reset()
+			s.log()
+
+			if out := buf.String(); out != s.expectedOutput {
+				t.Errorf("expected %q, got %q", s.expectedOutput, out)
+			}
+		})
+	}
+
+	if !logger.Handler().Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected the handler to be enabled at every level a Severity may raise")
+	}
+}
@@ -0,0 +1,133 @@
+package xerrors
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxSourceFiles bounds the files held by the source cache
+	maxSourceFiles = 32
+	// maxSourceFileSize is the size above which source files are not read
+	maxSourceFileSize = 1 << 20
+)
+
+// sourceCache holds the lines of source files, read on demand and evicted oldest first.
+// Files that can't be read are cached as nil.
+type sourceCache struct {
+	mu    sync.Mutex
+	max   int
+	files map[string][]string
+	order []string
+}
+
+var defaultSourceCache = newSourceCache(maxSourceFiles)
+
+func newSourceCache(max int) *sourceCache {
+	return &sourceCache{
+		max:   max,
+		files: make(map[string][]string),
+	}
+}
+
+// lines returns the lines of the file, or nil if it can't be read
+func (c *sourceCache) lines(path string) []string {
+	c.mu.Lock()
+	lines, ok := c.files[path]
+	c.mu.Unlock()
+
+	if ok {
+		return lines
+	}
+
+	lines = readSourceLines(path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.files[path]; ok {
+		// read concurrently
+		return lines
+	}
+
+	if len(c.order) == c.max {
+		delete(c.files, c.order[0])
+		c.order = c.order[1:]
+	}
+
+	c.files[path] = lines
+	c.order = append(c.order, path)
+
+	return lines
+}
+
+func readSourceLines(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxSourceFileSize {
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+// writeSource writes the n lines of source around line of the file, if it can be read, as:
+//
+//	  {line-1} | {source}
+//	> {line}   | {source}
+//	  {line+1} | {source}
+//
+// Each line is prefixed by indent and preceded by a newline, and the marked line is written in highlight if not empty.
+func writeSource(buf *bytes.Buffer, cache *sourceCache, path string, line, n int, indent, highlight string) {
+	lines := cache.lines(path)
+	if line < 1 || line > len(lines) {
+		return
+	}
+
+	first, last := line-n, line+n
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	width := len(strconv.Itoa(last))
+
+	for i := first; i <= last; i++ {
+		buf.WriteString("\n")
+		buf.WriteString(indent)
+
+		number := strconv.Itoa(i)
+		source := strings.TrimRight(lines[i-1], "\r")
+
+		if i != line {
+			buf.WriteString("  ")
+			buf.WriteString(strings.Repeat(" ", width-len(number)))
+			buf.WriteString(number)
+			buf.WriteString(" | ")
+			buf.WriteString(source)
+			continue
+		}
+
+		marked := "> " + strings.Repeat(" ", width-len(number)) + number + " | " + source
+		if highlight == "" {
+			buf.WriteString(marked)
+		} else {
+			buf.WriteString(highlight)
+			buf.WriteString(marked)
+			buf.WriteString(ansiReset)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package xerrors
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSourceCache(t *testing.T) {
+	dir := t.TempDir()
+
+	paths := make([]string, 3)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, string(rune('a'+i))+".go")
+		if err := os.WriteFile(paths[i], []byte("package foo\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newSourceCache(2)
+
+	for _, path := range paths {
+		if lines := c.lines(path); !reflect.DeepEqual(lines, []string{"package foo"}) {
+			t.Fatalf("unexpected lines %q", lines)
+		}
+	}
+
+	if lines := c.lines(filepath.Join(dir, "missing.go")); lines != nil {
+		t.Fatalf("expected no lines for a missing file, got %q", lines)
+	}
+
+	// the oldest files are evicted
+	if expected := paths[2:]; !reflect.DeepEqual(c.order[:1], expected) {
+		t.Fatalf("expected cached files %q, got %q", expected, c.order)
+	}
+
+	if len(c.files) != 2 {
+		t.Fatalf("expected 2 cached files, got %d", len(c.files))
+	}
+}
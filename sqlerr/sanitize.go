@@ -0,0 +1,218 @@
+package sqlerr
+
+import (
+	"strings"
+)
+
+// SanitizeQuery reduces a query to a fingerprint free of data, suitable for logs and for grouping errors:
+//   - string and numeric literals are replaced with "?", including double-quoted and dollar-quoted ("$$...$$" or
+//     "$tag$...$tag$") strings
+//   - lists of literals and "?" placeholders, as in "IN (1, 2, ?)", are reduced to a single "?"
+//   - comments are removed and whitespace is normalized, with tokens separated by single spaces except for
+//     parentheses, commas and dots, i.e. "count (*)", "(a, b)" and "t.id"
+//
+// Identifiers, keywords and other placeholders ("$1", ":name", "@name") are kept as they are.
+// Double-quoted text is a string literal in MySQL but an identifier in PostgreSQL and standard SQL, and is always
+// replaced so no data is leaked: quote identifiers with backticks, or leave them unquoted, to keep them.
+func SanitizeQuery(query string) string {
+	tokens := collapseValues(tokenize(query))
+
+	var b strings.Builder
+
+	for i, t := range tokens {
+		if i != 0 && !noSpaceBetween(tokens[i-1], t) {
+			b.WriteString(" ")
+		}
+		b.WriteString(t)
+	}
+
+	return b.String()
+}
+
+func noSpaceBetween(prev, next string) bool {
+	return prev == "(" || prev == "." || next == ")" || next == "," || next == "."
+}
+
+// collapseValues reduces runs of "?" separated by commas to a single "?"
+func collapseValues(tokens []string) []string {
+	out := tokens[:0]
+
+	for _, t := range tokens {
+		n := len(out)
+		if t == "?" && n >= 2 && out[n-1] == "," && out[n-2] == "?" {
+			out = out[:n-1]
+			continue
+		}
+		out = append(out, t)
+	}
+
+	return out
+}
+
+// tokenize splits the query into words, values ("?"), backtick-quoted identifiers, operators and punctuation,
+// dropping whitespace and comments
+func tokenize(query string) []string {
+	var out []string
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(query[i:], "--"):
+			if end := strings.IndexByte(query[i:], '\n'); end != -1 {
+				i += end
+			} else {
+				i = len(query)
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end != -1 {
+				i += 2 + end + 2
+			} else {
+				i = len(query)
+			}
+		case c == '\'' || c == '"':
+			i = skipQuoted(query, i)
+			out = append(out, "?")
+		case c == '$' && dollarTag(query, i) != "":
+			i = skipDollarQuoted(query, i)
+			out = append(out, "?")
+		case c == '`':
+			end := skipQuoted(query, i)
+			out = append(out, query[i:end])
+			i = end
+		case isDigit(c) || c == '.' && i+1 < len(query) && isDigit(query[i+1]):
+			i = skipNumber(query, i)
+			out = append(out, "?")
+		case c == '?':
+			i++
+			out = append(out, "?")
+		case (c == '$' || c == ':' || c == '@') && i+1 < len(query) && isWordByte(query[i+1]):
+			end := skipWord(query, i+1)
+			out = append(out, query[i:end])
+			i = end
+		case isWordByte(c):
+			end := skipWord(query, i)
+			out = append(out, query[i:end])
+			i = end
+		case strings.IndexByte(operatorBytes, c) != -1:
+			end := i + 1
+			for end < len(query) && strings.IndexByte(operatorBytes, query[end]) != -1 &&
+				!strings.HasPrefix(query[end:], "--") && !strings.HasPrefix(query[end:], "/*") {
+				end++
+			}
+			out = append(out, query[i:end])
+			i = end
+		default:
+			out = append(out, query[i:i+1])
+			i++
+		}
+	}
+
+	return out
+}
+
+const operatorBytes = "<>=!|&+-*/%^~:"
+
+// skipQuoted returns the index past the quoted section starting at i, where doubled quotes and backslashes escape.
+// Backslashes are not escapes in standard SQL (i.e. PostgreSQL's standard_conforming_strings), so if reading them as
+// such leaves an unbalanced remainder the section is read without them, and if that is unbalanced too the rest of the
+// query is taken as part of it so no literal is leaked.
+func skipQuoted(query string, i int) int {
+	end, escaped := scanQuoted(query, i, true)
+	if !escaped || balanced(query[end:], query[i]) {
+		return end
+	}
+
+	if end, _ = scanQuoted(query, i, false); balanced(query[end:], query[i]) {
+		return end
+	}
+
+	return len(query)
+}
+
+// scanQuoted returns the index past the quoted section starting at i, and whether any backslash escape was found
+func scanQuoted(query string, i int, backslashes bool) (int, bool) {
+	quote := query[i]
+	escaped := false
+
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			if backslashes {
+				escaped = true
+				j++
+			}
+		case quote:
+			if j+1 < len(query) && query[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1, escaped
+		}
+	}
+
+	return len(query), escaped
+}
+
+// balanced reports whether s holds an even number of quotes
+func balanced(s string, quote byte) bool {
+	return strings.Count(s, string(quote))%2 == 0
+}
+
+// dollarTag returns the opening tag ("$$" or "$tag$") of a dollar-quoted string starting at i, or "" if there is none
+func dollarTag(query string, i int) string {
+	j := i + 1
+	if j < len(query) && isDigit(query[j]) {
+		// a placeholder, as "$1"
+		return ""
+	}
+
+	for j < len(query) && query[j] != '$' && isWordByte(query[j]) {
+		j++
+	}
+
+	if j == len(query) || query[j] != '$' {
+		return ""
+	}
+
+	return query[i : j+1]
+}
+
+// skipDollarQuoted returns the index past the dollar-quoted string starting at i, which has no escapes
+func skipDollarQuoted(query string, i int) int {
+	tag := dollarTag(query, i)
+
+	end := strings.Index(query[i+len(tag):], tag)
+	if end == -1 {
+		return len(query)
+	}
+
+	return i + len(tag) + end + len(tag)
+}
+
+// skipNumber returns the index past the numeric literal starting at i, including decimals, exponents and hexadecimals
+func skipNumber(query string, i int) int {
+	for i < len(query) && (isWordByte(query[i]) || query[i] == '.' ||
+		(query[i] == '-' || query[i] == '+') && (query[i-1] == 'e' || query[i-1] == 'E')) {
+		i++
+	}
+	return i
+}
+
+func skipWord(query string, i int) int {
+	for i < len(query) && isWordByte(query[i]) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// isWordByte is true for the bytes of identifiers and keywords, including all non-ASCII bytes
+func isWordByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
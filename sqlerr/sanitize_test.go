@@ -0,0 +1,101 @@
+package sqlerr_test
+
+import (
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors/sqlerr"
+)
+
+func TestSanitizeQuery(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		query         string
+		expectedQuery string
+	}{
+		{
+			name:          "empty",
+			query:         "",
+			expectedQuery: "",
+		},
+		{
+			name:          "literals",
+			query:         "SELECT * FROM users WHERE name = 'o''brien' AND age > 42.5 AND score < -1e-3",
+			expectedQuery: "SELECT * FROM users WHERE name = ? AND age > ? AND score < - ?",
+		},
+		{
+			name:          "lists",
+			query:         "SELECT id FROM t WHERE id IN (1, 2, 3) AND name IN ('a','b') AND x IN (?, ?)",
+			expectedQuery: "SELECT id FROM t WHERE id IN (?) AND name IN (?) AND x IN (?)",
+		},
+		{
+			name:          "insert",
+			query:         "INSERT INTO t (a, b) VALUES ($1, 'foo'), ($2, 'bar')",
+			expectedQuery: "INSERT INTO t (a, b) VALUES ($1, ?), ($2, ?)",
+		},
+		{
+			name:          "placeholders",
+			query:         "UPDATE t SET a = :a, b = @b WHERE c = $3",
+			expectedQuery: "UPDATE t SET a = :a, b = @b WHERE c = $3",
+		},
+		{
+			name:          "identifiers",
+			query:         "SELECT `user`.`id`, `t1`.col2 FROM `user` JOIN t1 ON t1.uid = `user`.id",
+			expectedQuery: "SELECT `user`.`id`, `t1`.col2 FROM `user` JOIN t1 ON t1.uid = `user`.id",
+		},
+		{
+			name:          "doubleQuoted",
+			query:         `SELECT * FROM t WHERE email = "bob@x.com" AND name = "o""brien"`,
+			expectedQuery: "SELECT * FROM t WHERE email = ? AND name = ?",
+		},
+		{
+			name:          "dollarQuoted",
+			query:         "UPDATE t SET body = $$secret 'text'$$, note = $n$it's $$ here$n$ WHERE id = $1",
+			expectedQuery: "UPDATE t SET body = ?, note = ? WHERE id = $1",
+		},
+		{
+			name:          "unterminatedDollarQuoted",
+			query:         "SELECT $tag$secret",
+			expectedQuery: "SELECT ?",
+		},
+		{
+			name: "commentsAndWhitespace",
+			query: `SELECT a -- the a
+				FROM t /* secret 42 */
+				WHERE  b>=10`,
+			expectedQuery: "SELECT a FROM t WHERE b >= ?",
+		},
+		{
+			name:          "casts",
+			query:         "SELECT '2020-01-01'::date, count(*)",
+			expectedQuery: "SELECT ? :: date, count (*)",
+		},
+		{
+			name:          "unterminated",
+			query:         "SELECT 'secret",
+			expectedQuery: "SELECT ?",
+		},
+		{
+			name:          "backslashEscape",
+			query:         `SELECT * FROM t WHERE p = 'it\'s' AND q = 'hunter2'`,
+			expectedQuery: "SELECT * FROM t WHERE p = ? AND q = ?",
+		},
+		{
+			name:          "backslashNotEscape",
+			query:         `SELECT * FROM t WHERE p = 'C:\' AND q = 'hunter2'`,
+			expectedQuery: "SELECT * FROM t WHERE p = ? AND q = ?",
+		},
+		{
+			name:          "backslashTrailing",
+			query:         `SELECT * FROM t WHERE p = 'C:\'`,
+			expectedQuery: "SELECT * FROM t WHERE p = ?",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := sqlerr.SanitizeQuery(s.query); out != s.expectedQuery {
+				t.Errorf("expected %q, got %q", s.expectedQuery, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Package sqlerr wraps errors returned by database/sql with the operation and query that caused them.
+//
+// The query is sanitized (see SanitizeQuery) so errors can be logged and grouped without leaking the data in literals.
+package sqlerr
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// Op is a database/sql operation.
+type Op uint8
+
+const (
+	// OpQuery is for queries returning rows, including QueryRow.
+	OpQuery Op = iota
+	// OpExec is for statements not returning rows.
+	OpExec
+	// OpPrepare is for preparing statements.
+	OpPrepare
+	// OpBegin is for starting transactions.
+	OpBegin
+	// OpCommit is for committing transactions.
+	OpCommit
+	// OpRollback is for rolling back transactions.
+	OpRollback
+	// OpScan is for scanning rows.
+	OpScan
+)
+
+var opNames = [...]string{
+	OpQuery:    "query",
+	OpExec:     "exec",
+	OpPrepare:  "prepare",
+	OpBegin:    "begin",
+	OpCommit:   "commit",
+	OpRollback: "rollback",
+	OpScan:     "scan",
+}
+
+// String is the lower case name of the operation, i.e. "query".
+func (op Op) String() string {
+	if int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return "unknown"
+}
+
+// OpError is the payload describing the database/sql operation an error originated in.
+// Do not initialise an OpError directly, use Wrap or the wrapping functions of this package.
+type OpError struct {
+	op    Op
+	query string
+}
+
+// Op is a getter for the operation.
+func (err *OpError) Op() Op {
+	return err.op
+}
+
+// Query is a getter for the sanitized query, empty for operations with no query.
+func (err *OpError) Query() string {
+	return err.query
+}
+
+// ErrorToBuffer makes OpError implement xerrors.BufferError.
+// The format is "sql {op}[ {query}]", with the query quoted.
+func (err *OpError) ErrorToBuffer(buf *bytes.Buffer) {
+	buf.WriteString("sql ")
+	buf.WriteString(err.op.String())
+
+	if err.query != "" {
+		buf.WriteString(" \"")
+		buf.WriteString(err.query)
+		buf.WriteString("\"")
+	}
+}
+
+// Error is the string format of OpError.ErrorToBuffer
+func (err *OpError) Error() string {
+	return xerrors.BufferErrorToString(err)
+}
+
+// KeyValueErrorData makes OpError implement xerrors.KeyValueError, with keys "sql.op" and, if any, "sql.query".
+func (err *OpError) KeyValueErrorData() [][2]string {
+	out := [][2]string{{"sql.op", err.op.String()}}
+
+	if err.query != "" {
+		out = append(out, [2]string{"sql.query", err.query})
+	}
+
+	return out
+}
+
+// Wrap wraps err with an OpError for the operation and the sanitized query, as xerrors.Wrap does.
+// It returns nil for a nil err.
+func Wrap(err error, op Op, query string) error {
+	if err == nil {
+		return nil
+	}
+
+	return xerrors.Wrap(err, &OpError{op: op, query: SanitizeQuery(query)})
+}
+
+func isOpError(err error) bool {
+	_, ok := err.(*OpError)
+	return ok
+}
+
+// OpErrorOf returns the outermost OpError in err, if any.
+func OpErrorOf(err error) (*OpError, bool) {
+	opErr, ok := xerrors.Find(err, isOpError).(*OpError)
+	return opErr, ok
+}
+
+func is(target error) func(error) bool {
+	return func(err error) bool {
+		return err == target
+	}
+}
+
+// IsNoRows reports whether err holds sql.ErrNoRows.
+func IsNoRows(err error) bool {
+	return xerrors.Find(err, is(sql.ErrNoRows)) != nil
+}
+
+// IsTxDone reports whether err holds sql.ErrTxDone, from operating on a committed or rolled back transaction.
+func IsTxDone(err error) bool {
+	return xerrors.Find(err, is(sql.ErrTxDone)) != nil
+}
+
+// IsConnDone reports whether err holds sql.ErrConnDone or driver.ErrBadConn, from operating on a broken or closed
+// connection.
+func IsConnDone(err error) bool {
+	return xerrors.Find(err, func(err error) bool { return err == sql.ErrConnDone || err == driver.ErrBadConn }) != nil
+}
+
+// Querier is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// QueryContext calls q.QueryContext, wrapping any error with OpQuery.
+func QueryContext(ctx context.Context, q Querier, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	return rows, Wrap(err, OpQuery, query)
+}
+
+// QueryRowScan calls q.QueryRowContext and scans the row into dest, wrapping any error with OpQuery.
+// Use IsNoRows to check for no results.
+func QueryRowScan(ctx context.Context, q Querier, query string, args []interface{}, dest ...interface{}) error {
+	return Wrap(q.QueryRowContext(ctx, query, args...).Scan(dest...), OpQuery, query)
+}
+
+// ExecContext calls q.ExecContext, wrapping any error with OpExec.
+func ExecContext(ctx context.Context, q Querier, query string, args ...interface{}) (sql.Result, error) {
+	result, err := q.ExecContext(ctx, query, args...)
+	return result, Wrap(err, OpExec, query)
+}
+
+// PrepareContext calls q.PrepareContext, wrapping any error with OpPrepare.
+func PrepareContext(ctx context.Context, q Querier, query string) (*sql.Stmt, error) {
+	stmt, err := q.PrepareContext(ctx, query)
+	return stmt, Wrap(err, OpPrepare, query)
+}
+
+// BeginTx calls db.BeginTx, wrapping any error with OpBegin.
+func BeginTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*sql.Tx, error) {
+	tx, err := db.BeginTx(ctx, opts)
+	return tx, Wrap(err, OpBegin, "")
+}
+
+// Commit calls tx.Commit, wrapping any error with OpCommit.
+func Commit(tx *sql.Tx) error {
+	return Wrap(tx.Commit(), OpCommit, "")
+}
+
+// Rollback calls tx.Rollback, wrapping any error with OpRollback.
+func Rollback(tx *sql.Tx) error {
+	return Wrap(tx.Rollback(), OpRollback, "")
+}
+
+// Scan calls rows.Scan, wrapping any error with OpScan.
+func Scan(rows *sql.Rows, dest ...interface{}) error {
+	return Wrap(rows.Scan(dest...), OpScan, "")
+}
@@ -0,0 +1,247 @@
+package sqlerr_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+	"github.com/JavierZunzunegui/xerrors/sqlerr"
+)
+
+var errSyntax = xerrors.New("syntax error")
+
+// fakeDriver answers queries by their text: "fail" errors, "bad conn" breaks the connection, "empty" returns no rows
+// and anything else returns a single row with a single column
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if query == "fail" {
+		return nil, errSyntax
+	}
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if err := s.err(); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if err := s.err(); err != nil {
+		return nil, err
+	}
+	return &fakeRows{n: map[bool]int{true: 0, false: 1}[s.query == "empty"]}, nil
+}
+
+func (s *fakeStmt) err() error {
+	switch s.query {
+	case "fail":
+		return errSyntax
+	case "bad conn":
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+type fakeRows struct {
+	n int
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.n == 0 {
+		return io.EOF
+	}
+	r.n--
+	dest[0] = "not a number"
+	return nil
+}
+
+func init() {
+	sql.Register("sqlerr_fake", fakeDriver{})
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlerr_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func TestWrappers(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+
+	tx, err := sqlerr.BeginTx(ctx, db, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := sqlerr.Commit(tx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	scenarios := []struct {
+		name           string
+		f              func() error
+		expectedOutput string
+		expectedOp     sqlerr.Op
+		expectNoRows   bool
+		expectTxDone   bool
+		expectConnDone bool
+	}{
+		{
+			name: "query",
+			f: func() error {
+				_, err := sqlerr.QueryContext(ctx, db, "fail")
+				return err
+			},
+			expectedOutput: `sql query "fail": syntax error`,
+			expectedOp:     sqlerr.OpQuery,
+		},
+		{
+			name: "exec",
+			f: func() error {
+				_, err := sqlerr.ExecContext(ctx, db, "bad conn")
+				return err
+			},
+			expectedOutput: `sql exec "bad conn": driver: bad connection`,
+			expectedOp:     sqlerr.OpExec,
+			expectConnDone: true,
+		},
+		{
+			name: "prepare",
+			f: func() error {
+				_, err := sqlerr.PrepareContext(ctx, db, "fail")
+				return err
+			},
+			expectedOutput: `sql prepare "fail": syntax error`,
+			expectedOp:     sqlerr.OpPrepare,
+		},
+		{
+			name: "noRows",
+			f: func() error {
+				var id int
+				return sqlerr.QueryRowScan(ctx, db, "empty", nil, &id)
+			},
+			expectedOutput: `sql query "empty": sql: no rows in result set`,
+			expectedOp:     sqlerr.OpQuery,
+			expectNoRows:   true,
+		},
+		{
+			name:           "txDone",
+			f:              func() error { return sqlerr.Rollback(tx) },
+			expectedOutput: "sql rollback: sql: transaction has already been committed or rolled back",
+			expectedOp:     sqlerr.OpRollback,
+			expectTxDone:   true,
+		},
+		{
+			name: "scan",
+			f: func() error {
+				rows, err := sqlerr.QueryContext(ctx, db, "SELECT id FROM users WHERE name = 'foo'")
+				if err != nil {
+					return err
+				}
+				defer rows.Close()
+
+				rows.Next()
+
+				var id int
+				return sqlerr.Scan(rows, &id)
+			},
+			expectedOutput: `sql scan: sql: Scan error on column index 0, name "id": converting driver.Value type string ("not a number") to a int: invalid syntax`,
+			expectedOp:     sqlerr.OpScan,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.f()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if out := err.Error(); out != s.expectedOutput {
+				t.Errorf("expected %q, got %q", s.expectedOutput, out)
+			}
+
+			if opErr, ok := sqlerr.OpErrorOf(err); !ok || opErr.Op() != s.expectedOp {
+				t.Errorf("expected op %s, got %v", s.expectedOp, opErr)
+			}
+
+			if xerrors.Find(err, isStackError) == nil {
+				t.Error("expected a stack")
+			}
+
+			if out := sqlerr.IsNoRows(err); out != s.expectNoRows {
+				t.Errorf("expected IsNoRows %t", s.expectNoRows)
+			}
+
+			if out := sqlerr.IsTxDone(err); out != s.expectTxDone {
+				t.Errorf("expected IsTxDone %t", s.expectTxDone)
+			}
+
+			if out := sqlerr.IsConnDone(err); out != s.expectConnDone {
+				t.Errorf("expected IsConnDone %t", s.expectConnDone)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if err := sqlerr.Wrap(nil, sqlerr.OpQuery, "SELECT 1"); err != nil {
+		t.Errorf("expected nil, got %q", err)
+	}
+
+	err := sqlerr.Wrap(sql.ErrNoRows, sqlerr.OpQuery, "SELECT * FROM users WHERE id = 42")
+
+	opErr, ok := sqlerr.OpErrorOf(err)
+	if !ok {
+		t.Fatal("expected an OpError")
+	}
+
+	if out, expectedOut := opErr.Query(), "SELECT * FROM users WHERE id = ?"; out != expectedOut {
+		t.Errorf("expected query %q, got %q", expectedOut, out)
+	}
+
+	expectedData := [][2]string{{"sql.op", "query"}, {"sql.query", "SELECT * FROM users WHERE id = ?"}}
+	if data := opErr.KeyValueErrorData(); len(data) != 2 || data[0] != expectedData[0] || data[1] != expectedData[1] {
+		t.Errorf("expected data %q, got %q", expectedData, data)
+	}
+}
+
+func isStackError(err error) bool {
+	_, ok := err.(*xerrors.StackError)
+	return ok
+}
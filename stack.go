@@ -2,22 +2,35 @@ package xerrors
 
 import (
 	"bytes"
+	"path"
 	"runtime"
 	"strconv"
+	"strings"
 )
 
 func newStackError(opts StackOpts) *StackError {
 	frames := make([]uintptr, int(opts.Depth))
 	d := runtime.Callers(int(opts.Skip+2), frames)
 
-	return &StackError{
+	sErr := &StackError{
 		frames: frames[:d],
+		trim:   opts.Trim,
 	}
+
+	if opts.Goroutine != nil {
+		sErr.goroutine = newGoroutineInfo(opts.Goroutine)
+	}
+
+	return sErr
 }
 
-func isNotStackError(err error) bool {
+func isStackError(err error) bool {
 	_, ok := err.(*StackError)
-	return !ok
+	return ok
+}
+
+func isNotStackError(err error) bool {
+	return !isStackError(err)
 }
 
 // StackError holds a stack - a collection of frames capturing the program state at the time of creating its creation.
@@ -42,21 +55,60 @@ func isNotStackError(err error) bool {
 // FrameError trivially easily if the stack option was not favoured
 type StackError struct {
 	frames []uintptr
+
+	// symbolized holds the frames of StackErrors not captured by this process, see DecodeJSON.
+	symbolized []runtime.Frame
+
+	trim PathTrim
+
+	// goroutine is only set if requested via StackOpts.Goroutine
+	goroutine *GoroutineInfo
 }
 
 // ErrorToBuffer provides the default formatting of StackErrors and makes it implement BufferError.
 // The format is "{frame_format[0]} - {frame_format[1]} - ... - {frame_format[N-1]}" for a stack N frames deep.
-// Each frame format is "package.function_name:file_path:line_number"
+// Each frame format is "package.function_name:file_path:line_number", with the file path trimmed as by StackOpts.Trim.
+// If goroutine metadata was recorded the format is instead
+// "{goroutine}: {frame_format[0]} - ... - {frame_format[N-1]} - created by {function}[ in goroutine {parent}]", see
+// GoroutineInfo.String.
 func (err *StackError) ErrorToBuffer(buf *bytes.Buffer) {
+	if err.goroutine != nil {
+		err.goroutine.writeHeader(buf)
+		buf.WriteString(": ")
+		err.framesToBuffer(buf)
+
+		var createdBy bytes.Buffer
+		if err.goroutine.writeCreatedBy(&createdBy) {
+			buf.WriteString(" - ")
+			buf.Write(createdBy.Bytes())
+		}
+
+		return
+	}
+
+	err.framesToBuffer(buf)
+}
+
+func (err *StackError) framesToBuffer(buf *bytes.Buffer) {
+	if err.symbolized != nil {
+		for i, frame := range err.symbolized {
+			if i != 0 {
+				buf.WriteString(" - ")
+			}
+			formatFrame(frame, err.trim, buf)
+		}
+		return
+	}
+
 	frames := err.Frames()
 
 	frame, ok := frames.Next()
-	formatFrame(frame, buf)
+	formatFrame(frame, err.trim, buf)
 
 	for ok {
 		frame, ok = frames.Next()
 		buf.WriteString(" - ")
-		formatFrame(frame, buf)
+		formatFrame(frame, err.trim, buf)
 	}
 }
 
@@ -67,6 +119,7 @@ func (err *StackError) Error() string {
 
 // Frames exports access to all data held by the StackError.
 // It is intended to be used by custom Formatters that wish to convert StackErrors to strings in a specific manner.
+// StackErrors decoded via DecodeJSON or DecodeBinary have no frames here, use SymbolizedFrames for those.
 //
 // [PROPOSAL NOTES]
 //
@@ -75,7 +128,48 @@ func (err *StackError) Frames() *runtime.Frames {
 	return runtime.CallersFrames(err.frames)
 }
 
-func formatFrame(frame runtime.Frame, buf *bytes.Buffer) {
+// SymbolizedFrames returns all frames held by the StackError, resolved to their function, file and line.
+// Unlike Frames it also holds the frames of StackErrors decoded via DecodeJSON or DecodeBinary.
+// It allocates, prefer Frames where possible.
+func (err *StackError) SymbolizedFrames() []runtime.Frame {
+	if err.symbolized != nil {
+		return err.symbolized
+	}
+
+	out := make([]runtime.Frame, 0, len(err.frames))
+
+	frames := err.Frames()
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" || frame.File != "" {
+			out = append(out, frame)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return out
+}
+
+// Goroutine returns the metadata of the goroutine the stack was captured in, if requested via StackOpts.Goroutine.
+// It returns false otherwise, and for StackErrors decoded via DecodeJSON or DecodeBinary.
+func (err *StackError) Goroutine() (GoroutineInfo, bool) {
+	if err.goroutine == nil {
+		return GoroutineInfo{}, false
+	}
+
+	return *err.goroutine, true
+}
+
+// PathTrim is a getter for the trimming of file paths of the StackError, as set in StackOpts.Trim.
+// Custom Formatters may honor it via TrimPath.
+// It is 0 for StackErrors decoded via DecodeJSON or DecodeBinary, which have their paths as trimmed when encoded.
+func (err *StackError) PathTrim() PathTrim {
+	return err.trim
+}
+
+func formatFrame(frame runtime.Frame, trim PathTrim, buf *bytes.Buffer) {
 	if frame.Function != "" {
 		buf.WriteString(frame.Function)
 	}
@@ -83,8 +177,57 @@ func formatFrame(frame runtime.Frame, buf *bytes.Buffer) {
 		buf.WriteString(":")
 	}
 	if frame.File != "" {
-		buf.WriteString(frame.File)
+		buf.WriteString(trimPath(frame.Function, frame.File, trim, mainModule, mainPackage))
 		buf.WriteString(":")
 		buf.WriteString(strconv.Itoa(frame.Line))
 	}
 }
+
+// functionPackage returns the package path of a function name, as in runtime.Frame.Function
+func functionPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+
+	dot := strings.Index(function[slash+1:], ".")
+	if dot == -1 {
+		return function
+	}
+
+	return function[:slash+1+dot]
+}
+
+// IsStdlibFrame reports whether the frame belongs to the standard library.
+// It is intended for custom Formatters and integrations, as TrimPath.
+func IsStdlibFrame(frame runtime.Frame) bool {
+	return isStdlibFrame(frame.Function, frame.File, mainModule)
+}
+
+// isStdlibFrame reports whether a frame, by its function name and file, belongs to the standard library.
+// Standard library packages have no dot in the first element of their path, unlike the paths of most modules.
+// Those of the main module never belong to it, nor do those with a file outside of "{GOROOT}/src/{package}", as is
+// the case of dependencies replaced with local directories.
+func isStdlibFrame(function, file, mainModule string) bool {
+	if function == "" {
+		return false
+	}
+
+	pkg := functionPackage(function)
+	if pkg == "main" || (mainModule != "" && (pkg == mainModule || strings.HasPrefix(pkg, mainModule+"/"))) {
+		return false
+	}
+
+	first := pkg
+	if slash := strings.Index(pkg, "/"); slash != -1 {
+		first = pkg[:slash]
+	}
+
+	if strings.Contains(first, ".") {
+		return false
+	}
+
+	// relative files, as with -trimpath, can't tell the location apart
+	if !path.IsAbs(file) {
+		return true
+	}
+
+	return strings.Contains(file, "/src/"+pkg+"/")
+}
@@ -0,0 +1,73 @@
+package xerrors
+
+import "testing"
+
+func TestIsStdlibFrame(t *testing.T) {
+	const module = "myapp"
+
+	scenarios := []struct {
+		name         string
+		function     string
+		file         string
+		expectStdlib bool
+	}{
+		{
+			name:         "stdlib",
+			function:     "net/http.HandlerFunc.ServeHTTP",
+			file:         "/usr/local/go/src/net/http/server.go",
+			expectStdlib: true,
+		},
+		{
+			name:         "stdlibOtherGOROOT",
+			function:     "runtime.goexit",
+			file:         "/go/src/runtime/asm_amd64.s",
+			expectStdlib: true,
+		},
+		{
+			name:         "stdlibTrimmed",
+			function:     "net/http.HandlerFunc.ServeHTTP",
+			file:         "$GOROOT/src/net/http/server.go",
+			expectStdlib: true,
+		},
+		{
+			name:         "stdlibNoFile",
+			function:     "net/http.HandlerFunc.ServeHTTP",
+			expectStdlib: true,
+		},
+		{
+			name:     "module",
+			function: "github.com/foo/lib.Bar",
+			file:     "/home/ci/go/pkg/mod/github.com/foo/lib@v1.0.0/bar.go",
+		},
+		{
+			name:     "main",
+			function: "main.main",
+			file:     "/home/ci/app/main.go",
+		},
+		{
+			name:     "mainModule",
+			function: "myapp/internal/foo.Bar",
+			file:     "/home/ci/myapp/internal/foo/bar.go",
+		},
+		{
+			name:     "mainModuleRoot",
+			function: "myapp.Run",
+		},
+		{
+			name:     "replacedModule",
+			function: "mylib/foo.Bar",
+			file:     "/home/ci/mylib/foo/bar.go",
+		},
+		{
+			name: "unknown",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := isStdlibFrame(s.function, s.file, module); out != s.expectStdlib {
+				t.Errorf("expected stdlib %t for %s %s", s.expectStdlib, s.function, s.file)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package xerrors
+
+import (
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"unicode"
+)
+
+// PathTrim defines how the file paths of frames are shortened when formatted, see StackOpts.
+// Its values can be combined, the first one that applies to a frame is used.
+// Paths that none applies to are left as is.
+//
+// [PROPOSAL NOTES]
+//
+// Trimming is based on the function's package path rather than on the environment (GOROOT, GOMODCACHE...), so it
+// produces the same output regardless of the machine the code was built on.
+type PathTrim uint8
+
+const (
+	// TrimBasename reduces paths to the file name, i.e. "file.go".
+	// It applies to all frames, and so takes precedence over the others.
+	TrimBasename PathTrim = 1 << iota
+	// TrimGOROOT replaces the GOROOT of standard library files with "$GOROOT", i.e. "$GOROOT/src/net/http/server.go".
+	TrimGOROOT
+	// TrimModuleCache reduces files in the module cache to "{module}@{version}/{path}".
+	TrimModuleCache
+	// TrimModuleRoot makes files of the main module relative to its root, i.e. "internal/foo/foo.go".
+	TrimModuleRoot
+
+	// TrimAll applies all trimming except TrimBasename.
+	TrimAll = TrimGOROOT | TrimModuleCache | TrimModuleRoot
+)
+
+// mainModule is the path of the main module, empty if unknown
+var mainModule = func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path
+	}
+	return ""
+}()
+
+// mainPackage is the path of the main package, empty if unknown.
+// Its functions are named "main.{name}" rather than after its path, which is needed to place them in the main module.
+var mainPackage = func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Path
+	}
+	return ""
+}()
+
+// TrimPath returns the file path of the frame, shortened as defined by trim.
+// It is intended for custom Formatters, which may use StackError.PathTrim to honor the trimming of each StackError.
+func TrimPath(frame runtime.Frame, trim PathTrim) string {
+	return trimPath(frame.Function, frame.File, trim, mainModule, mainPackage)
+}
+
+func trimPath(function, file string, trim PathTrim, mainModule, mainPackage string) string {
+	if trim == 0 || file == "" {
+		return file
+	}
+
+	if trim&TrimBasename != 0 {
+		return path.Base(file)
+	}
+
+	if function == "" {
+		return file
+	}
+
+	pkg := functionPackage(function)
+	if pkg == "main" && mainPackage != "" {
+		pkg = mainPackage
+	}
+
+	if trim&TrimGOROOT != 0 && isStdlibFrame(function, file, mainModule) {
+		if i := strings.LastIndex(file, "/src/"+pkg+"/"); i != -1 {
+			return "$GOROOT" + file[i:]
+		}
+	}
+
+	if trim&TrimModuleCache != 0 {
+		// the module is a prefix of the package path, and is followed by "@{version}" in module cache paths
+		escaped := escapeModulePath(pkg)
+		for end := len(escaped); end > 0; end = strings.LastIndex(escaped[:end], "/") {
+			if i := strings.LastIndex(file, "/"+escaped[:end]+"@"); i != -1 {
+				return file[i+1:]
+			}
+		}
+	}
+
+	if trim&TrimModuleRoot != 0 && mainModule != "" && (pkg == mainModule || strings.HasPrefix(pkg, mainModule+"/")) {
+		// the directory of the package is its path relative to the module, from the module root
+		dir := path.Dir(file)
+		if rel := pkg[len(mainModule):]; strings.HasSuffix(dir, rel) {
+			return strings.TrimPrefix(file, dir[:len(dir)-len(rel)]+"/")
+		}
+	}
+
+	return file
+}
+
+// escapeModulePath escapes upper case letters as the module cache does, "!" followed by the lower case letter
+func escapeModulePath(p string) string {
+	if strings.IndexFunc(p, unicode.IsUpper) == -1 {
+		return p
+	}
+
+	var b strings.Builder
+	for _, r := range p {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
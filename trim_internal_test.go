@@ -0,0 +1,109 @@
+package xerrors
+
+import "testing"
+
+func TestTrimPath(t *testing.T) {
+	const (
+		module      = "github.com/foo/app"
+		mainPackage = "github.com/foo/app/cmd/app"
+	)
+
+	scenarios := []struct {
+		name         string
+		function     string
+		file         string
+		trim         PathTrim
+		expectedPath string
+	}{
+		{
+			name:         "none",
+			function:     "net/http.HandlerFunc.ServeHTTP",
+			file:         "/usr/local/go/src/net/http/server.go",
+			expectedPath: "/usr/local/go/src/net/http/server.go",
+		},
+		{
+			name:         "basename",
+			function:     "github.com/foo/app.(*Server).handle",
+			file:         "/home/ci/app/server.go",
+			trim:         TrimBasename | TrimAll,
+			expectedPath: "server.go",
+		},
+		{
+			name:         "GOROOT",
+			function:     "net/http.HandlerFunc.ServeHTTP",
+			file:         "/usr/local/go/src/net/http/server.go",
+			trim:         TrimAll,
+			expectedPath: "$GOROOT/src/net/http/server.go",
+		},
+		{
+			name:         "GOROOTNotStdlib",
+			function:     "github.com/foo/lib.Bar",
+			file:         "/home/ci/go/src/github.com/foo/lib/bar.go",
+			trim:         TrimGOROOT,
+			expectedPath: "/home/ci/go/src/github.com/foo/lib/bar.go",
+		},
+		{
+			name:         "moduleCache",
+			function:     "github.com/foo/lib/sub.Bar",
+			file:         "/home/ci/go/pkg/mod/github.com/foo/lib@v1.2.3/sub/bar.go",
+			trim:         TrimAll,
+			expectedPath: "github.com/foo/lib@v1.2.3/sub/bar.go",
+		},
+		{
+			name:         "moduleCacheEscaped",
+			function:     "github.com/BurntSushi/toml.Decode",
+			file:         "/cache/github.com/!burnt!sushi/toml@v1.0.0/decode.go",
+			trim:         TrimModuleCache,
+			expectedPath: "github.com/!burnt!sushi/toml@v1.0.0/decode.go",
+		},
+		{
+			name:         "moduleRoot",
+			function:     "github.com/foo/app/internal/db.(*DB).Query",
+			file:         "/home/ci/src/app/internal/db/db.go",
+			trim:         TrimAll,
+			expectedPath: "internal/db/db.go",
+		},
+		{
+			name:         "moduleRootPackage",
+			function:     "github.com/foo/app.Run",
+			file:         "/home/ci/src/app/run.go",
+			trim:         TrimModuleRoot,
+			expectedPath: "run.go",
+		},
+		{
+			name:         "moduleRootOtherModule",
+			function:     "github.com/foo/application.Run",
+			file:         "/home/ci/src/application/run.go",
+			trim:         TrimModuleRoot,
+			expectedPath: "/home/ci/src/application/run.go",
+		},
+		{
+			name:         "moduleRootMain",
+			function:     "main.main",
+			file:         "/home/ci/src/app/cmd/app/main.go",
+			trim:         TrimAll,
+			expectedPath: "cmd/app/main.go",
+		},
+		{
+			name:         "moduleRootMainMismatch",
+			function:     "main.main",
+			file:         "/home/ci/src/other/main.go",
+			trim:         TrimAll,
+			expectedPath: "/home/ci/src/other/main.go",
+		},
+		{
+			name:         "noFunction",
+			file:         "/home/ci/src/app/run.go",
+			trim:         TrimAll,
+			expectedPath: "/home/ci/src/app/run.go",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if out := trimPath(s.function, s.file, s.trim, module, mainPackage); out != s.expectedPath {
+				t.Errorf("expected %q, got %q", s.expectedPath, out)
+			}
+		})
+	}
+}
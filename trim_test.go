@@ -0,0 +1,45 @@
+package xerrors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+func TestStackOpts_Trim(t *testing.T) {
+	err := xerrors.WrapWithOpts(nil, xerrors.New("foo"), xerrors.StackOpts{Depth: 1, Trim: xerrors.TrimBasename})
+
+	stackErr, ok := xerrors.Find(err, isStackError).(*xerrors.StackError)
+	if !ok {
+		t.Fatal("expected a StackError")
+	}
+
+	if stackErr.PathTrim() != xerrors.TrimBasename {
+		t.Errorf("expected TrimBasename, got %d", stackErr.PathTrim())
+	}
+
+	if out := stackErr.Error(); !strings.HasSuffix(out, ".TestStackOpts_Trim:trim_test.go:11") {
+		t.Errorf("expected the file path trimmed, got %q", out)
+	}
+
+	// trimming applies to encoded errors
+	decoded, decodeErr := xerrors.DecodeJSON(xerrors.EncodeJSON(err))
+	if decodeErr != nil {
+		t.Fatalf("unexpected decoding error: %s", decodeErr)
+	}
+
+	if out, expectedOut := xerrors.Find(decoded, isStackError).Error(), stackErr.Error(); out != expectedOut {
+		t.Errorf("expected decoded stack %q, got %q", expectedOut, out)
+	}
+
+	frame := stackErr.SymbolizedFrames()[0]
+	if out := xerrors.TrimPath(frame, 0); out != frame.File {
+		t.Errorf("expected the untrimmed path %q, got %q", frame.File, out)
+	}
+
+	p := xerrors.NewPrinter(xerrors.NewMultilineFormatter(xerrors.MultilineOpts{Trim: xerrors.TrimAll}))
+	if out := p.String(err); !strings.Contains(out, "\n\t") || strings.Contains(out, "\n\ttrim_test.go") {
+		t.Errorf("expected the formatter trimming to take precedence, got %q", out)
+	}
+}
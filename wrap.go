@@ -11,6 +11,8 @@ const defaultDepth = 10
 type WrappingError struct {
 	payload error
 	next    *WrappingError
+	// internal is the chain hidden behind this payload, see Opaque
+	internal *WrappingError
 }
 
 func isWrappingError(err error) bool {
@@ -46,6 +48,13 @@ var defaultStackOpts = StackOpts{
 type StackOpts struct {
 	Skip  uint8
 	Depth uint8
+	// Trim shortens the file paths of frames when the StackError is formatted, see PathTrim.
+	// The stacks added by Wrap and the other functions of this package are not trimmed, only those of WrapWithOpts
+	// with Trim set are. Formatters may trim regardless, see MultilineOpts.Trim.
+	Trim PathTrim
+	// Goroutine records metadata identifying the goroutine along with the stack, see StackError.Goroutine.
+	// It is not recorded if nil, as it requires reading the whole stack of the goroutine.
+	Goroutine *GoroutineOpts
 }
 
 // Wrap produces a WrappingError out of two errors and is the standard way users should produce these.
@@ -144,10 +153,12 @@ func merge(err, payload error) *WrappingError {
 	if pErr, ok := payload.(*WrappingError); ok {
 		// avoid doing this, payload should not be WrappingError as it causes many allocations
 		current.payload = pErr.payload
+		current.internal = pErr.internal
 
 		for pErr = pErr.next; pErr != nil; current, pErr = current.next, pErr.next {
 			current.next = &WrappingError{
-				payload: pErr.payload,
+				payload:  pErr.payload,
+				internal: pErr.internal,
 			}
 		}
 	} else {
@@ -0,0 +1,62 @@
+package xerrorstest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+var update = flag.Bool("xerrorstest.update", false, "update the golden files of xerrorstest.AssertGolden")
+
+// filePathRegexp matches file paths followed by a line number, as in stack frames
+var filePathRegexp = regexp.MustCompile(`(?:\b[A-Za-z]:)?[^\s:"'()]*[/\\]([^/\\\s:"'()]+\.go):[0-9]+`)
+
+// Normalize makes printed stacks independent of the machine and of line changes.
+// File paths are replaced with their base name and line numbers with "N", i.e. "/home/ci/src/foo/bar.go:12" is
+// normalized as "bar.go:N".
+func Normalize(s string) string {
+	return filePathRegexp.ReplaceAllString(s, "${1}:N")
+}
+
+// AssertGolden asserts the output of p for err, normalized (see Normalize), matches the content of the golden file.
+// If the test is run with the flag -xerrorstest.update, the golden file is written instead.
+//
+// Stacks include frames from the testing package and runtime, which may vary between go versions.
+// Golden errors are best produced with limited stack depths (see xerrors.WrapWithOpts).
+func AssertGolden(tb testing.TB, p *xerrors.Printer, err error, path string) bool {
+	tb.Helper()
+
+	got := []byte(Normalize(p.String(err)))
+
+	if *update {
+		if writeErr := os.MkdirAll(filepath.Dir(path), 0o755); writeErr != nil {
+			tb.Errorf("failed to create golden file directory: %s", writeErr)
+			return false
+		}
+
+		if writeErr := os.WriteFile(path, got, 0o644); writeErr != nil {
+			tb.Errorf("failed to update golden file: %s", writeErr)
+			return false
+		}
+
+		return true
+	}
+
+	want, readErr := os.ReadFile(path)
+	if readErr != nil {
+		tb.Errorf("failed to read golden file, run with -xerrorstest.update to create it: %s", readErr)
+		return false
+	}
+
+	if !bytes.Equal(got, want) {
+		tb.Errorf("output does not match golden file %s\ngot:\n%s\nwant:\n%s", path, got, want)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,78 @@
+// Package xerrorstest provides test assertions for wrapped errors.
+//
+// All assertions report failures via testing.TB.Errorf and return whether they passed, so tests can decide whether to
+// carry on.
+package xerrorstest
+
+import (
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+)
+
+// AssertSimilar asserts got and want are xerrors.Similar, reporting their xerrors.Diff otherwise.
+func AssertSimilar(tb testing.TB, got, want error) bool {
+	tb.Helper()
+
+	if xerrors.Similar(got, want) {
+		return true
+	}
+
+	tb.Errorf(
+		"errors are not similar\ngot:  %s\nwant: %s\ndiff (got/want):\n%s",
+		errorString(got), errorString(want), xerrors.Diff(got, want),
+	)
+
+	return false
+}
+
+// AssertContains asserts err xerrors.Contains target, reporting their xerrors.Diff otherwise.
+func AssertContains(tb testing.TB, err, target error) bool {
+	tb.Helper()
+
+	if xerrors.Contains(err, target) {
+		return true
+	}
+
+	tb.Errorf(
+		"error does not contain target\nerr:    %s\ntarget: %s\ndiff (err/target):\n%s",
+		errorString(err), errorString(target), xerrors.Diff(err, target),
+	)
+
+	return false
+}
+
+// AssertFindTyped asserts err holds a payload of type T, and returns the first such payload.
+// See xerrors.FindTyped.
+func AssertFindTyped[T error](tb testing.TB, err error) (T, bool) {
+	tb.Helper()
+
+	var target T
+
+	out, ok := xerrors.FindTyped(err, target).(T)
+	if !ok {
+		tb.Errorf("error holds no payload of type %T\nerr: %s", target, errorString(err))
+	}
+
+	return out, ok
+}
+
+// AssertHasStack asserts err holds at least one xerrors.StackError.
+func AssertHasStack(tb testing.TB, err error) bool {
+	tb.Helper()
+
+	if xerrors.FindTyped(err, (*xerrors.StackError)(nil)) != nil {
+		return true
+	}
+
+	tb.Errorf("error holds no stack\nerr: %s", errorString(err))
+
+	return false
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
@@ -0,0 +1,180 @@
+package xerrorstest_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/JavierZunzunegui/xerrors"
+	"github.com/JavierZunzunegui/xerrors/xerrorstest"
+)
+
+// fakeTB records failures instead of failing the test
+type fakeTB struct {
+	testing.TB
+	failures []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.failures = append(tb.failures, fmt.Sprintf(format, args...))
+}
+
+type fooError struct{}
+
+func (fooError) Error() string { return "foo" }
+
+func TestAssertions(t *testing.T) {
+	wrapped := xerrors.Wrap(xerrors.New("bar"), fooError{})
+
+	scenarios := []struct {
+		name            string
+		assert          func(testing.TB) bool
+		expectedFailure string
+	}{
+		{
+			name: "similarPass",
+			assert: func(tb testing.TB) bool {
+				return xerrorstest.AssertSimilar(tb, wrapped, xerrors.Wrap(xerrors.New("bar"), fooError{}))
+			},
+		},
+		{
+			name: "similarFail",
+			assert: func(tb testing.TB) bool {
+				return xerrorstest.AssertSimilar(tb, wrapped, xerrors.Wrap(xerrors.New("baz"), fooError{}))
+			},
+			expectedFailure: `! [1/1] message: xerrors.string "bar" != "baz"`,
+		},
+		{
+			name:   "containsPass",
+			assert: func(tb testing.TB) bool { return xerrorstest.AssertContains(tb, wrapped, xerrors.New("bar")) },
+		},
+		{
+			name:            "containsFail",
+			assert:          func(tb testing.TB) bool { return xerrorstest.AssertContains(tb, wrapped, xerrors.New("baz")) },
+			expectedFailure: "error does not contain target",
+		},
+		{
+			name: "findTypedPass",
+			assert: func(tb testing.TB) bool {
+				_, ok := xerrorstest.AssertFindTyped[fooError](tb, wrapped)
+				return ok
+			},
+		},
+		{
+			name: "findTypedFail",
+			assert: func(tb testing.TB) bool {
+				_, ok := xerrorstest.AssertFindTyped[*xerrors.PanicError](tb, wrapped)
+				return ok
+			},
+			expectedFailure: "error holds no payload of type *xerrors.PanicError",
+		},
+		{
+			name:   "hasStackPass",
+			assert: func(tb testing.TB) bool { return xerrorstest.AssertHasStack(tb, wrapped) },
+		},
+		{
+			name:            "hasStackFail",
+			assert:          func(tb testing.TB) bool { return xerrorstest.AssertHasStack(tb, xerrors.New("bar")) },
+			expectedFailure: "error holds no stack",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+
+		t.Run(scenario.name, func(t *testing.T) {
+			tb := &fakeTB{}
+			ok := scenario.assert(tb)
+
+			if scenario.expectedFailure == "" {
+				if !ok || len(tb.failures) != 0 {
+					t.Fatalf("expected assertion to pass, got failures %q", tb.failures)
+				}
+				return
+			}
+
+			if ok || len(tb.failures) != 1 {
+				t.Fatalf("expected assertion to fail once, got failures %q", tb.failures)
+			}
+
+			if !strings.Contains(tb.failures[0], scenario.expectedFailure) {
+				t.Fatalf("expected failure to contain %q, got %q", scenario.expectedFailure, tb.failures[0])
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	const (
+		in          = "pkg.foo:/home/ci/go/src/pkg/foo.go:12 - pkg.bar:C:\\src\\pkg\\bar.go:3 - main.main:main.go:1"
+		expectedOut = "pkg.foo:foo.go:N - pkg.bar:bar.go:N - main.main:main.go:1"
+	)
+
+	if out := xerrorstest.Normalize(in); out != expectedOut {
+		t.Fatalf("expected %q got %q", expectedOut, out)
+	}
+}
+
+// allFormatter writes every payload, StackErrors included, in its own line
+type allFormatter struct {
+	currentErr *xerrors.WrappingError
+	firstEntry bool
+}
+
+func newAllFormatter() xerrors.Formatter { return &allFormatter{} }
+
+func (f *allFormatter) Init(wErr *xerrors.WrappingError) {
+	f.currentErr = wErr
+	f.firstEntry = true
+}
+
+func (f *allFormatter) Next() error {
+	if f.currentErr == nil {
+		return nil
+	}
+
+	out := f.currentErr.Payload()
+	f.currentErr = f.currentErr.Next()
+
+	return out
+}
+
+func (f *allFormatter) CustomFormat(error, *bytes.Buffer) bool { return false }
+
+func (f *allFormatter) Append(w *bytes.Buffer, msg []byte) {
+	if f.firstEntry {
+		f.firstEntry = false
+	} else {
+		w.WriteString("\n")
+	}
+
+	w.Write(msg)
+}
+
+func goldenErrFunc() error {
+	return xerrors.WrapWithOpts(nil, xerrors.New("foo"), xerrors.StackOpts{Depth: 1})
+}
+
+func TestAssertGolden(t *testing.T) {
+	err := xerrors.Wrap(goldenErrFunc(), xerrors.New("bar"))
+	p := xerrors.NewPrinter(xerrors.NewColonFormatter)
+
+	xerrorstest.AssertGolden(t, p, err, "testdata/colon.golden")
+
+	stackPrinter := xerrors.NewPrinter(newAllFormatter)
+
+	xerrorstest.AssertGolden(t, stackPrinter, err, "testdata/stack.golden")
+
+	if flag.Lookup("xerrorstest.update").Value.String() == "true" {
+		return
+	}
+
+	tb := &fakeTB{}
+	if xerrorstest.AssertGolden(tb, p, xerrors.New("baz"), "testdata/colon.golden") {
+		t.Fatal("expected mismatched golden file to fail")
+	}
+}